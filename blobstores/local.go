@@ -0,0 +1,91 @@
+package blobstores
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalBlobStore keeps blobs as files in a directory of the local filesystem
+type LocalBlobStore struct {
+	Dir string
+}
+
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("error creating blob store directory %s: %s", dir, err.Error())
+	}
+
+	return &LocalBlobStore{
+		Dir: dir,
+	}, nil
+}
+
+// path returns the path of the file holding the blob key.
+// Keys escaping the store directory are rejected.
+func (s *LocalBlobStore) path(key string) (string, error) {
+	p := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if rel, err := filepath.Rel(s.Dir, p); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid blob key %s", key)
+	}
+	return p, nil
+}
+
+func (s *LocalBlobStore) Put(key string, r io.Reader, size int64, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+		return fmt.Errorf("error storing blob %s: %s", key, err.Error())
+	}
+
+	// Write to a temporary file first so a failed upload never leaves a partial blob
+	f, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("error storing blob %s: %s", key, err.Error())
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("error storing blob %s: %s", key, err.Error())
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error storing blob %s: %s", key, err.Error())
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("error storing blob %s: %s", key, err.Error())
+	}
+
+	return nil
+}
+
+func (s *LocalBlobStore) Get(key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading blob %s: %s", key, err.Error())
+	}
+
+	return f, nil
+}
+
+func (s *LocalBlobStore) Delete(key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting blob %s: %s", key, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package blobstores
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	s, err := NewLocalBlobStore(t.TempDir())
+	assert.NoError(t, err)
+
+	t.Run("Put and get a blob", func(t *testing.T) {
+		err := s.Put("tasks/0/abc", strings.NewReader("content"), 7, "text/plain")
+		assert.NoError(t, err)
+
+		b, err := s.Get("tasks/0/abc")
+		assert.NoError(t, err)
+		defer b.Close()
+		content, _ := ioutil.ReadAll(b)
+		assert.Equal(t, "content", string(content))
+	})
+
+	t.Run("Get non existing blob", func(t *testing.T) {
+		_, err := s.Get("tasks/0/nope")
+		assert.Error(t, err)
+	})
+
+	t.Run("Keys can't escape the store directory", func(t *testing.T) {
+		assert.Error(t, s.Put("../escape", strings.NewReader("content"), 7, "text/plain"))
+		_, err := s.Get("../../etc/passwd")
+		assert.Error(t, err)
+		assert.Error(t, s.Delete(""))
+	})
+
+	t.Run("Delete a blob", func(t *testing.T) {
+		assert.NoError(t, s.Delete("tasks/0/abc"))
+		_, err := s.Get("tasks/0/abc")
+		assert.Error(t, err)
+
+		// Deleting a missing blob is not an error
+		assert.NoError(t, s.Delete("tasks/0/abc"))
+	})
+}
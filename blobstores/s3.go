@@ -0,0 +1,76 @@
+package blobstores
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3BlobStore keeps blobs as objects of a bucket of an S3 compatible storage
+type S3BlobStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// S3Options holds the settings to reach an S3 compatible storage
+type S3Options struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+func NewS3BlobStore(opts S3Options) (*S3BlobStore, error) {
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating s3 client: %s", err.Error())
+	}
+
+	return &S3BlobStore{
+		client: client,
+		bucket: opts.Bucket,
+	}, nil
+}
+
+func (s *S3BlobStore) Put(key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(context.Background(), s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("error storing blob %s: %s", key, err.Error())
+	}
+
+	return nil
+}
+
+func (s *S3BlobStore) Get(key string) (io.ReadCloser, error) {
+	o, err := s.client.GetObject(context.Background(), s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error reading blob %s: %s", key, err.Error())
+	}
+
+	// GetObject is lazy, make sure the object exists before handing it out
+	if _, err := o.Stat(); err != nil {
+		o.Close()
+		return nil, fmt.Errorf("error reading blob %s: %s", key, err.Error())
+	}
+
+	return o, nil
+}
+
+func (s *S3BlobStore) Delete(key string) error {
+	if err := s.client.RemoveObject(context.Background(), s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("error deleting blob %s: %s", key, err.Error())
+	}
+
+	return nil
+}
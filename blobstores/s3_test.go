@@ -0,0 +1,141 @@
+package blobstores
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is a minimal stand-in of an S3 compatible storage
+// supporting the object operations used by S3BlobStore
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		objects: make(map[string][]byte),
+		types:   make(map[string]string),
+	}
+}
+
+// decodeAWSChunked decodes a body sent with the aws-chunked content encoding
+func decodeAWSChunked(r io.Reader) ([]byte, error) {
+	var out bytes.Buffer
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.Split(strings.TrimSpace(line), ";")[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return out.Bytes(), nil
+		}
+		if _, err := io.CopyN(&out, br, size); err != nil {
+			return nil, err
+		}
+		// Trailing CRLF of the chunk
+		if _, err := br.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := r.URL.Path
+	switch r.Method {
+	case http.MethodPut:
+		var body []byte
+		var err error
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			body, err = decodeAWSChunked(r.Body)
+		} else {
+			body, err = ioutil.ReadAll(r.Body)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = body
+		s.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		body, ok := s.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			}
+			return
+		}
+		w.Header().Set("Content-Type", s.types[key])
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3BlobStore(t *testing.T) {
+	fake := newFakeS3()
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s, err := NewS3BlobStore(S3Options{
+		Endpoint:  strings.TrimPrefix(srv.URL, "http://"),
+		Bucket:    "attachments",
+		Region:    "us-east-1",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	assert.NoError(t, err)
+
+	t.Run("Put and get a blob", func(t *testing.T) {
+		err := s.Put("tasks/0/abc", strings.NewReader("content"), 7, "text/plain")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("content"), fake.objects["/attachments/tasks/0/abc"])
+		assert.Equal(t, "text/plain", fake.types["/attachments/tasks/0/abc"])
+
+		b, err := s.Get("tasks/0/abc")
+		assert.NoError(t, err)
+		defer b.Close()
+		content, _ := ioutil.ReadAll(b)
+		assert.Equal(t, "content", string(content))
+	})
+
+	t.Run("Get non existing blob", func(t *testing.T) {
+		_, err := s.Get("tasks/0/nope")
+		assert.Error(t, err)
+	})
+
+	t.Run("Delete a blob", func(t *testing.T) {
+		assert.NoError(t, s.Delete("tasks/0/abc"))
+		assert.Empty(t, fake.objects)
+	})
+}
@@ -0,0 +1,172 @@
+// Package certs serves TLS with certificates reloaded when their files change
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options configures the certificates of the server
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// Bundle of the CAs the client certificates are verified against,
+	// client certificates aren't requested if empty
+	ClientCAFile string
+	// Whether clients must send a certificate, when ClientCAFile is given
+	ClientAuth tls.ClientAuthType
+}
+
+// ParseClientAuth parses the policy of verification of the client certificates:
+// "require" or "verify_if_given"
+func ParseClientAuth(s string) (tls.ClientAuthType, error) {
+	switch s {
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	}
+	return tls.NoClientCert, fmt.Errorf("invalid client auth %s: must be one of require, verify_if_given", s)
+}
+
+// Reloader holds the certificates of the server and loads them again when their files change
+type Reloader struct {
+	opts Options
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	// Modification times and sizes of the files when they were last loaded
+	version string
+}
+
+// NewReloader returns a Reloader holding the certificates of the files of opts.
+// Both the certificate and the key files must be given.
+func NewReloader(opts Options) (*Reloader, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, fmt.Errorf("both the certificate and the key files must be set")
+	}
+	r := &Reloader{opts: opts}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// files returns the files the certificates are loaded from
+func (r *Reloader) files() []string {
+	files := []string{r.opts.CertFile, r.opts.KeyFile}
+	if r.opts.ClientCAFile != "" {
+		files = append(files, r.opts.ClientCAFile)
+	}
+	return files
+}
+
+// fileVersion identifies the version of the files by their modification times and sizes.
+// Files are stat'ed through symlinks, so swapping the target of a link, as done
+// by Kubernetes to update mounted secrets, changes the version.
+func (r *Reloader) fileVersion() (string, error) {
+	var v []string
+	for _, f := range r.files() {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		v = append(v, fmt.Sprintf("%s:%d:%d", f, info.ModTime().UnixNano(), info.Size()))
+	}
+	return strings.Join(v, ","), nil
+}
+
+// Reload loads the certificates again if their files changed since they were last loaded.
+// It returns whether they were reloaded. The current certificates are kept on errors.
+func (r *Reloader) Reload() (bool, error) {
+	version, err := r.fileVersion()
+	if err != nil {
+		return false, fmt.Errorf("failed to read the certificates: %w", err)
+	}
+	r.mu.RLock()
+	unchanged := version == r.version
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load the certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to read the client CAs: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("no certificate found in the client CAs %s", r.opts.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert, r.clientCAs, r.version = &cert, clientCAs, version
+	return true, nil
+}
+
+// Run checks the files of the certificates every interval and reloads them when they change,
+// until the context is done. A zero or negative interval disables the reloading, it returns at once.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				log.Printf("failed to reload the TLS certificates: %s", err.Error())
+			} else if reloaded {
+				log.Print("TLS certificates reloaded")
+			}
+		}
+	}
+}
+
+// TLSConfig returns the configuration of a server using the current certificates,
+// for each new connection
+func (r *Reloader) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// http.Server works on a copy of its TLSConfig, the protocols
+		// it would add are lost in the configurations returned by GetConfigForClient
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		c := base.Clone()
+		c.GetConfigForClient = nil
+		c.Certificates = []tls.Certificate{*r.cert}
+		if r.clientCAs != nil {
+			c.ClientCAs = r.clientCAs
+			c.ClientAuth = r.opts.ClientAuth
+		}
+		return c, nil
+	}
+	return base
+}
@@ -0,0 +1,210 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCA signs the certificates of the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a certificate and its key signed by the CA, PEM encoded
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	assert.NoError(t, os.WriteFile(path, data, 0600))
+	// Make sure the change is seen whatever the precision of the file system
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestParseClientAuth(t *testing.T) {
+	a, err := ParseClientAuth("require")
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, a)
+	a, err = ParseClientAuth("verify_if_given")
+	assert.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, a)
+	_, err = ParseClientAuth("none")
+	assert.Error(t, err)
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	opts := Options{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	now := time.Now()
+	cert, key := ca.issue(t, 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, opts.CertFile, cert, now)
+	writeFile(t, opts.KeyFile, key, now)
+	writeFile(t, opts.ClientCAFile, ca.pem, now)
+
+	_, err := NewReloader(Options{CertFile: opts.CertFile, KeyFile: filepath.Join(dir, "missing.key")})
+	assert.Error(t, err)
+	_, err = NewReloader(Options{KeyFile: opts.KeyFile})
+	assert.Error(t, err)
+	_, err = NewReloader(Options{CertFile: opts.CertFile})
+	assert.Error(t, err)
+
+	r, err := NewReloader(opts)
+	assert.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = r.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientCert, clientKey := ca.issue(t, 20, x509.ExtKeyUsageClientAuth)
+	clientPair, err := tls.X509KeyPair(clientCert, clientKey)
+	assert.NoError(t, err)
+
+	// get returns the serial number of the certificate served
+	get := func(withCert bool) (int64, error) {
+		cfg := &tls.Config{RootCAs: roots}
+		if withCert {
+			cfg.Certificates = []tls.Certificate{clientPair}
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+		res, err := client.Get(srv.URL)
+		if err != nil {
+			return 0, err
+		}
+		defer res.Body.Close()
+		return res.TLS.PeerCertificates[0].SerialNumber.Int64(), nil
+	}
+
+	t.Run("Serve with client certificates", func(t *testing.T) {
+		serial, err := get(true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), serial)
+	})
+
+	t.Run("Reject clients without certificate", func(t *testing.T) {
+		_, err := get(false)
+		assert.Error(t, err)
+	})
+
+	t.Run("Keep the certificates when the files don't change", func(t *testing.T) {
+		reloaded, err := r.Reload()
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+	})
+
+	t.Run("Reload the certificates", func(t *testing.T) {
+		cert, key := ca.issue(t, 11, x509.ExtKeyUsageServerAuth)
+		writeFile(t, opts.CertFile, cert, now.Add(time.Minute))
+		writeFile(t, opts.KeyFile, key, now.Add(time.Minute))
+
+		reloaded, err := r.Reload()
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+
+		serial, err := get(true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), serial)
+	})
+
+	t.Run("Keep the certificates when the new ones are invalid", func(t *testing.T) {
+		writeFile(t, opts.KeyFile, []byte("not a key"), now.Add(2*time.Minute))
+
+		reloaded, err := r.Reload()
+		assert.Error(t, err)
+		assert.False(t, reloaded)
+
+		serial, err := get(true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), serial)
+	})
+
+	t.Run("Reload the client CAs", func(t *testing.T) {
+		// The new CA doesn't know the client certificate anymore
+		other := newTestCA(t)
+		cert, key := ca.issue(t, 12, x509.ExtKeyUsageServerAuth)
+		writeFile(t, opts.CertFile, cert, now.Add(3*time.Minute))
+		writeFile(t, opts.KeyFile, key, now.Add(3*time.Minute))
+		writeFile(t, opts.ClientCAFile, other.pem, now.Add(3*time.Minute))
+
+		_, err := r.Reload()
+		assert.NoError(t, err)
+		_, err = get(true)
+		assert.Error(t, err)
+	})
+
+	t.Run("Reloading disabled by a zero interval", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			r.Run(context.Background(), 0)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.Fail(t, "Run didn't return")
+		}
+	})
+}
@@ -51,6 +51,51 @@ func (c *Config) setDefaults() {
 	c.SetDefault("APP_CONFIG_NAME", ".env")
 	c.SetDefault("APP_CONFIG_PATH", ".")
 
+	// Set default HTTP server options
+	c.SetDefault("SERVER_READ_HEADER_TIMEOUT", "10s")
+	c.SetDefault("SERVER_READ_TIMEOUT", "30s")
+	c.SetDefault("SERVER_WRITE_TIMEOUT", "60s")
+	c.SetDefault("SERVER_IDLE_TIMEOUT", "120s")
+	c.SetDefault("SERVER_SHUTDOWN_DELAY", "5s")    // Time to fail readiness before draining connections on SIGTERM
+	c.SetDefault("SERVER_SHUTDOWN_TIMEOUT", "30s") // Grace period to drain connections on SIGTERM
+
+	// Set default TLS options
+	c.SetDefault("TLS_CERT_FILE", "") // Plain HTTP is served if both files are empty
+	c.SetDefault("TLS_KEY_FILE", "")
+	c.SetDefault("TLS_CLIENT_CA_FILE", "")     // Verifies client certificates against the CAs if set
+	c.SetDefault("TLS_CLIENT_AUTH", "require") // Availables: "require", "verify_if_given"
+	c.SetDefault("TLS_RELOAD_INTERVAL", "30s") // Interval between checks of changes of the certificate files
+
+	// Set default CORS options
+	c.SetDefault("CORS_ALLOWED_ORIGINS", "") // eg: "https://app.example.com". CORS is disabled if empty
+	c.SetDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE")
+	c.SetDefault("CORS_ALLOWED_HEADERS", "Content-Type,X-User,X-Request-ID,X-API-Key")
+	c.SetDefault("CORS_EXPOSED_HEADERS", "X-Request-ID,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After")
+	c.SetDefault("CORS_ALLOW_CREDENTIALS", false)
+	c.SetDefault("CORS_MAX_AGE", "10m")
+
+	// Set default logging options
+	c.SetDefault("LOG_LEVEL", "info")  // Availables: "debug", "info", "warn", "error"
+	c.SetDefault("LOG_FORMAT", "json") // Availables: "json", "text"
+
+	// Set default rate limiting options
+	c.SetDefault("RATELIMIT_DEFAULT", "") // Limit of every route, eg: "600/m". Unlimited if empty
+	c.SetDefault("RATELIMIT_ROUTES", "")  // eg: "POST /task=10/m,GET /healthz=none"
+	c.SetDefault("RATELIMIT_API_KEY_HEADER", "X-API-Key")
+	c.SetDefault("RATELIMIT_API_KEYS", "") // Comma separated keys limited by key rather than by IP address
+	c.SetDefault("RATELIMIT_TRUST_PROXY", false)
+	c.SetDefault("RATELIMIT_STORE", "memory") // Availables: "memory", "redis"
+	c.SetDefault("RATELIMIT_REDIS_ADDR", "localhost:6379")
+	c.SetDefault("RATELIMIT_REDIS_PASSWORD", "")
+	c.SetDefault("RATELIMIT_REDIS_DB", 0)
+
+	// Set default tracing options
+	c.SetDefault("TRACING_EXPORTER", "none") // Availables: "none", "otlp"
+	c.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4318")
+	c.SetDefault("TRACING_OTLP_INSECURE", false)
+	c.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	c.SetDefault("TRACING_SHUTDOWN_TIMEOUT", "5s") // Time to flush the remaining spans once connections are drained
+
 	// Set default database options
 	c.SetDefault("DATABASE_TYPE", "memory") // Availables: "memory"
 	c.SetDefault("DATABASE_HOST", "")
@@ -58,4 +103,43 @@ func (c *Config) setDefaults() {
 	c.SetDefault("DATABASE_USERNAME", "")
 	c.SetDefault("DATABASE_PASSWORD", "")
 	c.SetDefault("DATABASE_NAME", "")
+
+	// Set default workflow options
+	c.SetDefault("WORKFLOW_STATUSES", "TODO,INPROGRESS,DONE,BLOCKED,CANCELLED") // The first status is given to new tasks
+	c.SetDefault("WORKFLOW_TRANSITIONS", "TODO->INPROGRESS,TODO->BLOCKED,TODO->CANCELLED,"+
+		"INPROGRESS->TODO,INPROGRESS->DONE,INPROGRESS->BLOCKED,INPROGRESS->CANCELLED,"+
+		"BLOCKED->TODO,BLOCKED->INPROGRESS,BLOCKED->CANCELLED,"+
+		"DONE->INPROGRESS")
+
+	// Set default trash options
+	c.SetDefault("TRASH_RETENTION", "720h")
+	c.SetDefault("TRASH_PURGE_INTERVAL", "1h")
+
+	// Set default board options
+	c.SetDefault("BOARD_WIP_LIMITS", "") // eg: "INPROGRESS:5,BLOCKED:3", 0 for no limit
+
+	// Set default markdown options
+	c.SetDefault("MARKDOWN_TASK_URL", "/task/{id}")       // {id} is replaced by the id of the referenced task
+	c.SetDefault("MARKDOWN_MENTION_URL", "/users/{user}") // {user} is replaced by the mentioned user
+
+	// Set default link options
+	c.SetDefault("LINK_TYPES", "duplicates:duplicated_by,relates_to,caused_by:causes") // name:inverse, or name for symmetric types
+
+	// Set default checklist options
+	c.SetDefault("CHECKLIST_AUTO_DONE", false)
+
+	// Set default task positions options
+	c.SetDefault("POSITION_MAX_LENGTH", 32)
+
+	// Set default attachments options
+	c.SetDefault("ATTACHMENTS_MAX_SIZE", 10<<20) // In bytes
+	c.SetDefault("ATTACHMENTS_ALLOWED_TYPES", "image/*,text/plain,application/pdf,application/zip,application/x-gzip")
+	c.SetDefault("BLOBSTORE_TYPE", "local") // Availables: "local", "s3"
+	c.SetDefault("BLOBSTORE_LOCAL_PATH", "./attachments")
+	c.SetDefault("BLOBSTORE_S3_ENDPOINT", "")
+	c.SetDefault("BLOBSTORE_S3_BUCKET", "")
+	c.SetDefault("BLOBSTORE_S3_REGION", "")
+	c.SetDefault("BLOBSTORE_S3_ACCESS_KEY", "")
+	c.SetDefault("BLOBSTORE_S3_SECRET_KEY", "")
+	c.SetDefault("BLOBSTORE_S3_USE_SSL", true)
 }
@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo-go/models"
+)
+
+// Size of the memory used to parse multipart forms, the remaining is stored on disk
+const multipartMemory = 10 << 20
+
+// WithAttachments enables file attachments stored in blobStore.
+// Files bigger than maxSize bytes or whose sniffed MIME type isn't part of
+// allowedTypes are rejected. Allowed types can be wildcards such as "image/*".
+func WithAttachments(blobStore models.BlobStore, maxSize int64, allowedTypes []string) Option {
+	return func(h *BaseHandler) {
+		h.blobStore = blobStore
+		h.maxAttachmentSize = maxSize
+		h.allowedAttachments = allowedTypes
+	}
+}
+
+// isAllowedAttachment returns whether files of the given content type can be attached
+func (h *BaseHandler) isAllowedAttachment(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, t := range h.allowedAttachments {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// newBlobKey returns a new unique key for a blob attached to the task
+func newBlobKey(taskID uint64) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+// removeAttachmentBlobs deletes the blobs of the attachments of a purged task.
+// Failures are only logged as the task is already gone.
+func (h *BaseHandler) removeAttachmentBlobs(t *models.Task) {
+	if h.blobStore == nil {
+		return
+	}
+	for _, a := range t.Attachments {
+		if err := h.blobStore.Delete(a.Key); err != nil {
+			log.Printf("failed to delete attachment id %d of task id %d: %s", a.Id, t.Id, err.Error())
+		}
+	}
+}
+
+// taskAttachment returns the task identified by the "id" route variable and
+// its attachment identified by the "attachmentId" route variable.
+// It responds with an error and returns nil otherwise.
+func (h *BaseHandler) taskAttachment(w http.ResponseWriter, r *http.Request) (*models.Task, *models.Attachment) {
+	if h.blobStore == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("attachments are not available"))
+		return nil, nil
+	}
+
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil, nil
+	}
+	attachmentID, err := uintVar(r, "attachmentId")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil, nil
+	}
+
+	t, err := h.tasks(r).GetTaskByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return nil, nil
+	}
+	for k := range t.Attachments {
+		if t.Attachments[k].Id == attachmentID {
+			a := t.Attachments[k]
+			return t, &a
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte("not found"))
+	return nil, nil
+}
+
+// UploadAttachment attaches the file sent as the "file" field of a multipart form to a task
+func (h *BaseHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	if h.blobStore == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("attachments are not available"))
+		return
+	}
+
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	// Leave some room for the multipart envelope around the file
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxAttachmentSize+multipartMemory)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			w.Write([]byte(fmt.Sprintf("attachments must not exceed %d bytes", h.maxAttachmentSize)))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.maxAttachmentSize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(fmt.Sprintf("attachments must not exceed %d bytes", h.maxAttachmentSize)))
+		return
+	}
+
+	// Don't trust the content type sent by the client, sniff it
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !h.isAllowedAttachment(contentType) {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		w.Write([]byte(fmt.Sprintf("attachments of type %s are not allowed", contentType)))
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	key, err := newBlobKey(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err := h.blobStore.Put(key, file, header.Size, contentType); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	// Keep a copy of the task before the update for the history
+	var old models.Task
+	if t, err := h.tasks(r).GetTaskByID(id); err == nil {
+		old = *t
+	}
+
+	a := models.Attachment{
+		Filename:    header.Filename,
+		ContentType: contentType,
+		Size:        header.Size,
+		Key:         key,
+	}
+	attachmentID, err := h.tasks(r).AddAttachment(id, a)
+	if err != nil {
+		// The task has been deleted during the upload
+		h.blobStore.Delete(key)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	t, err := h.tasks(r).GetTaskByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	h.recordHistory(r, models.HistoryActionUpdate, id, &old, t)
+
+	for _, v := range t.Attachments {
+		if v.Id == attachmentID {
+			a = v
+		}
+	}
+	resp, err := json.Marshal(a)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resp)
+}
+
+func (h *BaseHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	_, a := h.taskAttachment(w, r)
+	if a == nil {
+		return
+	}
+
+	b, err := h.blobStore.Get(a.Key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	defer b.Close()
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, b); err != nil {
+		log.Printf("failed to send attachment id %d: %s", a.Id, err.Error())
+	}
+}
+
+func (h *BaseHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	t, a := h.taskAttachment(w, r)
+	if a == nil {
+		return
+	}
+
+	// Keep a copy of the task before the update for the history
+	old := *t
+	if err := h.tasks(r).DeleteAttachment(t.Id, a.Id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+	if n, err := h.tasks(r).GetTaskByID(t.Id); err == nil {
+		h.recordHistory(r, models.HistoryActionUpdate, t.Id, &old, n)
+	}
+
+	if err := h.blobStore.Delete(a.Key); err != nil {
+		log.Printf("failed to delete attachment id %d of task id %d: %s", a.Id, t.Id, err.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-go/blobstores"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// Smallest valid PNG image
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89\x00\x00\x00\rIDATx\x9cc\x00\x01\x00\x00\x05\x00\x01\r\n-\xb4\x00\x00\x00\x00IEND\xaeB`\x82")
+
+func multipartFile(t *testing.T, filename string, content []byte) (*bytes.Buffer, string) {
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	fw, err := mw.CreateFormFile("file", filename)
+	assert.NoError(t, err)
+	fw.Write(content)
+	mw.Close()
+	return &b, mw.FormDataContentType()
+}
+
+func TestAttachments(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	store, _ := blobstores.NewLocalBlobStore(t.TempDir())
+	h := NewBaseHandler(db, WithAttachments(store, 1024, []string{"image/*", "text/plain"}))
+	db.CreateTask(models.Task{Title: "title"}, models.TaskCondition{})
+
+	upload := func(id, filename string, content []byte) *httptest.ResponseRecorder {
+		body, contentType := multipartFile(t, filename, content)
+		req, _ := http.NewRequest("POST", "/task/"+id+"/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		res := httptest.NewRecorder()
+		h.UploadAttachment(res, req)
+		return res
+	}
+	attachment := map[string]string{"id": "0", "attachmentId": "0"}
+
+	t.Run("Upload attachment", func(t *testing.T) {
+		res := upload("0", "screenshot.png", png)
+		var a models.Attachment
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &a))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, "screenshot.png", a.Filename)
+		assert.Equal(t, "image/png", a.ContentType)
+		assert.Equal(t, int64(len(png)), a.Size)
+
+		task, _ := db.GetTaskByID(0)
+		assert.Len(t, task.Attachments, 1)
+	})
+
+	t.Run("Upload disallowed type", func(t *testing.T) {
+		res := upload("0", "page.html", []byte("<html><body>hello</body></html>"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.Code)
+	})
+
+	t.Run("Upload too big attachment", func(t *testing.T) {
+		res := upload("0", "big.log", bytes.Repeat([]byte("a"), 2048))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+	})
+
+	t.Run("Upload to non existing task", func(t *testing.T) {
+		res := upload("99", "screenshot.png", png)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Download attachment", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/task/0/attachments/0", nil)
+		req = mux.SetURLVars(req, attachment)
+		res := httptest.NewRecorder()
+		h.GetAttachment(res, req)
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, png, res.Body.Bytes())
+		assert.Equal(t, "image/png", res.Result().Header.Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=screenshot.png`, res.Result().Header.Get("Content-Disposition"))
+	})
+
+	t.Run("Delete attachment", func(t *testing.T) {
+		task, _ := db.GetTaskByID(0)
+		key := task.Attachments[0].Key
+
+		req, _ := http.NewRequest("DELETE", "/task/0/attachments/0", nil)
+		req = mux.SetURLVars(req, attachment)
+		res := httptest.NewRecorder()
+		h.DeleteAttachment(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		task, _ = db.GetTaskByID(0)
+		assert.Empty(t, task.Attachments)
+		_, err := store.Get(key)
+		assert.Error(t, err)
+
+		res = httptest.NewRecorder()
+		h.GetAttachment(res, req)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Blobs are removed when the task is purged", func(t *testing.T) {
+		upload("0", "screenshot.png", png)
+		task, _ := db.GetTaskByID(0)
+		key := task.Attachments[0].Key
+
+		req, _ := http.NewRequest("DELETE", "/task/0?hard=true", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "0"})
+		h.DeleteTask(httptest.NewRecorder(), req)
+
+		_, err := store.Get(key)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"todo-go/models"
+)
+
+// WithWIPLimits sets the maximum number of tasks by status.
+// Statuses without limit are omitted.
+func WithWIPLimits(limits map[models.Status]int) Option {
+	return func(h *BaseHandler) {
+		h.wipLimits = limits
+	}
+}
+
+// taskCondition returns the condition of a write putting a task in the status:
+// the work in progress limit of the status, and when current is given, its status
+// as the write was checked against it. Repositories apply it atomically with the write.
+func (h *BaseHandler) taskCondition(current *models.Task, s models.Status) models.TaskCondition {
+	c := models.TaskCondition{WIPLimit: h.wipLimits[s]}
+	if current != nil {
+		c.Status = current.Status
+	}
+	return c
+}
+
+// conditionFailed returns whether a write failed on its condition, see taskCondition
+func conditionFailed(err error) bool {
+	return errors.Is(err, models.ErrWIPLimitReached) || errors.Is(err, models.ErrTaskChanged)
+}
+
+// GetBoard returns the tasks grouped in a column per status of the workflow,
+// ordered by position. Tasks are filtered like GetTasks.
+func (h *BaseHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	f, err := taskFilterFromQuery(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	// Columns are always ordered by position
+	f.Sort = nil
+
+	b := models.NewBoard(h.workflow, h.wipLimits, h.tasks(r).FindTasks(f))
+	resp, err := json.Marshal(b)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoard(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithWIPLimits(map[models.Status]int{models.StatusInProgress: 1}))
+	db.CreateTask(models.Task{Status: models.StatusToDo}, models.TaskCondition{})
+	db.CreateTask(models.Task{Status: models.StatusToDo}, models.TaskCondition{})
+	db.CreateTask(models.Task{Status: models.StatusInProgress}, models.TaskCondition{})
+
+	board := func() models.Board {
+		req, _ := http.NewRequest("GET", "/board", nil)
+		res := httptest.NewRecorder()
+		h.GetBoard(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
+
+		var b models.Board
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &b))
+		return b
+	}
+	move := func(id, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/task/"+id+"/move", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		res := httptest.NewRecorder()
+		h.MoveTask(res, req)
+		return res
+	}
+
+	t.Run("Get board", func(t *testing.T) {
+		b := board()
+		assert.Len(t, b.Columns, len(models.DefaultWorkflow().Statuses))
+		assert.Equal(t, models.Status(models.StatusToDo), b.Columns[0].Status)
+		assert.Equal(t, 2, b.Columns[0].Count)
+		assert.Equal(t, models.Status(models.StatusInProgress), b.Columns[1].Status)
+		assert.Equal(t, 1, b.Columns[1].WIPLimit)
+	})
+
+	t.Run("Move to a full column", func(t *testing.T) {
+		res := move("0", `{"status":"INPROGRESS","before":2}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+	})
+
+	t.Run("Move along the workflow", func(t *testing.T) {
+		res := move("2", `{"status":"DONE"}`)
+		assert.Equal(t, http.StatusOK, res.Code)
+		res = move("0", `{"status":"INPROGRESS"}`)
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		b := board()
+		assert.Equal(t, 1, b.Columns[0].Count)
+		assert.Equal(t, uint64(0), b.Columns[1].Tasks[0].Id)
+		assert.Equal(t, uint64(2), b.Columns[2].Tasks[0].Id)
+	})
+
+	t.Run("Move against the workflow", func(t *testing.T) {
+		res := move("1", `{"status":"DONE"}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		res = move("1", `{"status":"UNKNOWN"}`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Reorder a column", func(t *testing.T) {
+		db.CreateTask(models.Task{Status: models.StatusToDo}, models.TaskCondition{})
+		res := move("3", `{"before":1}`)
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		b := board()
+		assert.Equal(t, uint64(3), b.Columns[0].Tasks[0].Id)
+		assert.Equal(t, uint64(1), b.Columns[0].Tasks[1].Id)
+	})
+	t.Run("Create and update in a full column", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/task", strings.NewReader(`{"title":"new","status":"INPROGRESS"}`))
+		res := httptest.NewRecorder()
+		h.CreateTask(res, req)
+		assert.Equal(t, http.StatusConflict, res.Code)
+
+		update := func(id, body string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("PUT", "/task/"+id, strings.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			res := httptest.NewRecorder()
+			h.UpdateTask(res, req)
+			return res
+		}
+		res = update("1", `{"title":"todo","status":"INPROGRESS"}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		task, _ := db.GetTaskByID(1)
+		assert.Equal(t, models.Status(models.StatusToDo), task.Status)
+
+		// Tasks already in the full column can still be edited
+		res = update("0", `{"title":"in progress","status":"INPROGRESS"}`)
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, 1, board().Columns[1].Count)
+	})
+}
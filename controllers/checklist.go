@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"todo-go/models"
+)
+
+// WithChecklistAutoDone moves tasks to the DONE status once all the items
+// of their checklist are checked, when the workflow allows it
+func WithChecklistAutoDone(enabled bool) Option {
+	return func(h *BaseHandler) {
+		h.checklistAutoDone = enabled
+	}
+}
+
+// checklistTask returns a copy of the task identified by the "id" route variable.
+// It responds with an error and returns nil if there is none.
+func (h *BaseHandler) checklistTask(w http.ResponseWriter, r *http.Request) *models.Task {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	t, err := h.tasks(r).GetTaskByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return nil
+	}
+
+	// Keep a copy of the task before the update for the history
+	old := *t
+	return &old
+}
+
+// completeChecklist moves the task to the DONE status if its checklist is done
+// and auto completion is enabled. Only the status changes, under the same condition
+// as an update, so the task stays as it is if DONE is full or its status changed.
+// It returns the task as it is after the move.
+func (h *BaseHandler) completeChecklist(r *http.Request, t *models.Task) *models.Task {
+	if !h.checklistAutoDone || !models.ChecklistDone(t.Checklist) || !h.workflow.CanTransition(t.Status, models.StatusDone) {
+		return t
+	}
+
+	old := *t
+	m := models.TaskMove{Status: models.StatusDone, Condition: h.taskCondition(t, models.StatusDone)}
+	n, err := h.tasks(r).MoveTask(t.Id, m)
+	if err != nil {
+		return t
+	}
+	h.recordHistory(r, models.HistoryActionUpdate, t.Id, &old, n)
+	return n
+}
+
+// writeTask responds with the task
+func writeTask(w http.ResponseWriter, t *models.Task, code int) {
+	resp, err := json.Marshal(t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(resp)
+}
+
+// AddChecklistItem appends an item to the checklist of a task
+func (h *BaseHandler) AddChecklistItem(w http.ResponseWriter, r *http.Request) {
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	var item models.ChecklistItem
+	if err := json.Unmarshal(rBody, &item); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if strings.TrimSpace(item.Text) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("checklist item text must not be empty"))
+		return
+	}
+
+	old := h.checklistTask(w, r)
+	if old == nil {
+		return
+	}
+
+	itemID, err := h.tasks(r).AddChecklistItem(old.Id, item)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	t, err := h.tasks(r).GetTaskByID(old.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	h.recordHistory(r, models.HistoryActionUpdate, old.Id, old, t)
+
+	for _, v := range t.Checklist {
+		if v.Id == itemID {
+			item = v
+		}
+	}
+	resp, err := json.Marshal(item)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resp)
+}
+
+// ToggleChecklistItem checks or unchecks an item of the checklist of a task.
+// It responds with the task to reflect its completion and status.
+func (h *BaseHandler) ToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	itemID, err := uintVar(r, "itemId")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	old := h.checklistTask(w, r)
+	if old == nil {
+		return
+	}
+
+	if err := h.tasks(r).ToggleChecklistItem(old.Id, itemID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	t, err := h.tasks(r).GetTaskByID(old.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	h.recordHistory(r, models.HistoryActionUpdate, old.Id, old, t)
+
+	writeTask(w, h.completeChecklist(r, t), http.StatusOK)
+}
+
+// MoveChecklistItem moves an item of the checklist of a task to the index sent in the request body
+func (h *BaseHandler) MoveChecklistItem(w http.ResponseWriter, r *http.Request) {
+	itemID, err := uintVar(r, "itemId")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	var m struct {
+		Index *int `json:"index"`
+	}
+	if err := json.Unmarshal(rBody, &m); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if m.Index == nil || *m.Index < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("index must be set and must not be negative"))
+		return
+	}
+
+	old := h.checklistTask(w, r)
+	if old == nil {
+		return
+	}
+
+	if err := h.tasks(r).MoveChecklistItem(old.Id, itemID, *m.Index); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	t, err := h.tasks(r).GetTaskByID(old.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	h.recordHistory(r, models.HistoryActionUpdate, old.Id, old, t)
+
+	writeTask(w, t, http.StatusOK)
+}
+
+// DeleteChecklistItem removes an item from the checklist of a task.
+// Removing the last unchecked item completes the checklist.
+func (h *BaseHandler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	itemID, err := uintVar(r, "itemId")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	old := h.checklistTask(w, r)
+	if old == nil {
+		return
+	}
+
+	if err := h.tasks(r).DeleteChecklistItem(old.Id, itemID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	if t, err := h.tasks(r).GetTaskByID(old.Id); err == nil {
+		h.recordHistory(r, models.HistoryActionUpdate, old.Id, old, t)
+		h.completeChecklist(r, t)
+	}
+}
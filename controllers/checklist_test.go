@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklist(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithHistoryRepository(db), WithChecklistAutoDone(true))
+	db.CreateTask(models.Task{Title: "title", Status: models.StatusInProgress}, models.TaskCondition{})
+
+	do := func(handler http.HandlerFunc, vars map[string]string, body io.Reader) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/", body)
+		req = mux.SetURLVars(req, vars)
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+	task := map[string]string{"id": "0"}
+	item := func(id string) map[string]string {
+		return map[string]string{"id": "0", "itemId": id}
+	}
+
+	t.Run("Add items", func(t *testing.T) {
+		res := do(h.AddChecklistItem, task, strings.NewReader(`{"text":"first"}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		var i models.ChecklistItem
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &i))
+		assert.Equal(t, "first", i.Text)
+
+		res = do(h.AddChecklistItem, task, strings.NewReader(`{"text":"second"}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+
+		res = do(h.AddChecklistItem, task, strings.NewReader(`{"text":" "}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = do(h.AddChecklistItem, map[string]string{"id": "99"}, strings.NewReader(`{"text":"text"}`))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Reorder items", func(t *testing.T) {
+		res := do(h.MoveChecklistItem, item("1"), strings.NewReader(`{"index":0}`))
+		assert.Equal(t, http.StatusOK, res.Code)
+		var n models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &n))
+		assert.Equal(t, uint64(1), n.Checklist[0].Id)
+
+		res = do(h.MoveChecklistItem, item("1"), strings.NewReader(`{}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = do(h.MoveChecklistItem, item("99"), strings.NewReader(`{"index":0}`))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Toggle items", func(t *testing.T) {
+		res := do(h.ToggleChecklistItem, item("0"), nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var n models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &n))
+		assert.Equal(t, 50, *n.ChecklistCompletion)
+		assert.Equal(t, models.Status(models.StatusInProgress), n.Status)
+
+		res = do(h.ToggleChecklistItem, item("99"), nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Complete the checklist", func(t *testing.T) {
+		res := do(h.ToggleChecklistItem, item("1"), nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var n models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &n))
+		assert.Equal(t, 100, *n.ChecklistCompletion)
+		assert.Equal(t, models.Status(models.StatusDone), n.Status)
+
+		history := db.GetTaskHistory(0)
+		assert.Equal(t, "status", history[len(history)-1].Changes[0].Field)
+	})
+
+	t.Run("Delete the last unchecked item", func(t *testing.T) {
+		db.UpdateTask(models.Task{Id: 0, Title: "title", Status: models.StatusInProgress}, models.TaskCondition{})
+		do(h.ToggleChecklistItem, item("1"), nil)
+
+		res := do(h.DeleteChecklistItem, item("1"), nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		n, _ := db.GetTaskByID(0)
+		assert.Len(t, n.Checklist, 1)
+		assert.Equal(t, models.Status(models.StatusDone), n.Status)
+	})
+}
+
+func TestChecklistAutoDoneWIPLimit(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithChecklistAutoDone(true), WithWIPLimits(map[models.Status]int{models.StatusDone: 1}))
+	db.CreateTask(models.Task{Title: "title", Status: models.StatusInProgress}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "done", Status: models.StatusDone}, models.TaskCondition{})
+	db.AddChecklistItem(0, models.ChecklistItem{Text: "item"})
+
+	req, _ := http.NewRequest("POST", "/", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "0", "itemId": "0"})
+	res := httptest.NewRecorder()
+	h.ToggleChecklistItem(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	// DONE is full, the task keeps its status
+	n, _ := db.GetTaskByID(0)
+	assert.Equal(t, 100, *models.ChecklistCompletion(n.Checklist))
+	assert.Equal(t, models.Status(models.StatusInProgress), n.Status)
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"todo-go/models"
+)
+
+// WithCommentRepository sets the repository storing the comments of tasks
+func WithCommentRepository(commentRepo models.CommentRepository) Option {
+	return func(h *BaseHandler) {
+		h.commentRepo = commentRepo
+	}
+}
+
+// taskComment returns the comment identified by the "commentId" route variable
+// if it belongs to the task identified by the "id" route variable.
+// It responds with an error and returns nil otherwise.
+func (h *BaseHandler) taskComment(w http.ResponseWriter, r *http.Request) *models.Comment {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+	commentID, err := uintVar(r, "commentId")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	if h.commentRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("comments are not available"))
+		return nil
+	}
+
+	// Comments of trashed tasks are kept but not reachable
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return nil
+	}
+	c, err := h.commentRepo.GetCommentByID(commentID)
+	if err != nil || c.TaskId != id {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return nil
+	}
+
+	return c
+}
+
+// decodeComment reads the comment sent in the request body
+func decodeComment(w http.ResponseWriter, r *http.Request) *models.Comment {
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	var c models.Comment
+	if err := json.Unmarshal(rBody, &c); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+	if strings.TrimSpace(c.Body) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("comment body must not be empty"))
+		return nil
+	}
+
+	return &c
+}
+
+func (h *BaseHandler) GetTaskComments(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.commentRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("comments are not available"))
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	resp, err := json.Marshal(h.commentRepo.GetTaskComments(id))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+func (h *BaseHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.commentRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("comments are not available"))
+		return
+	}
+
+	c := decodeComment(w, r)
+	if c == nil {
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	c.TaskId = id
+	c.Author = userFromRequest(r)
+	commentID, err := h.commentRepo.CreateComment(*c)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	n, err := h.commentRepo.GetCommentByID(commentID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	resp, err := json.Marshal(n)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resp)
+}
+
+func (h *BaseHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	current := h.taskComment(w, r)
+	if current == nil {
+		return
+	}
+
+	// Only the author can edit a comment
+	if current.Author != userFromRequest(r) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(fmt.Sprintf("comment can only be edited by %s", current.Author)))
+		return
+	}
+
+	c := decodeComment(w, r)
+	if c == nil {
+		return
+	}
+
+	c.Id = current.Id
+	if err := h.commentRepo.UpdateComment(*c); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BaseHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	c := h.taskComment(w, r)
+	if c == nil {
+		return
+	}
+
+	// Only the author can delete a comment
+	if c.Author != userFromRequest(r) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(fmt.Sprintf("comment can only be deleted by %s", c.Author)))
+		return
+	}
+
+	if err := h.commentRepo.DeleteComment(c.Id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+}
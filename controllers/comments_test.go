@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComments(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithCommentRepository(db))
+	db.CreateTask(models.Task{Title: "title"}, models.TaskCondition{})
+
+	do := func(handler http.HandlerFunc, method string, vars map[string]string, user string, body io.Reader) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/", body)
+		req = mux.SetURLVars(req, vars)
+		req.Header.Set("X-User", user)
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+	task := map[string]string{"id": "0"}
+	comment := map[string]string{"id": "0", "commentId": "0"}
+
+	t.Run("Create comment", func(t *testing.T) {
+		res := do(h.CreateComment, "POST", task, "john", strings.NewReader(`{"body":"**hello**","author":"jane"}`))
+		var c models.Comment
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &c))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
+		assert.Equal(t, "john", c.Author)
+		assert.Equal(t, uint64(0), c.TaskId)
+	})
+
+	t.Run("Create invalid comments", func(t *testing.T) {
+		res := do(h.CreateComment, "POST", task, "john", strings.NewReader(`{"body":"  "}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+
+		res = do(h.CreateComment, "POST", map[string]string{"id": "99"}, "john", strings.NewReader(`{"body":"hello"}`))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("List comments", func(t *testing.T) {
+		res := do(h.GetTaskComments, "GET", task, "", nil)
+		var comments []models.Comment
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &comments))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Len(t, comments, 1)
+	})
+
+	t.Run("Edit comment", func(t *testing.T) {
+		res := do(h.UpdateComment, "PUT", comment, "jane", strings.NewReader(`{"body":"edited"}`))
+		assert.Equal(t, http.StatusForbidden, res.Code)
+
+		res = do(h.UpdateComment, "PUT", comment, "john", strings.NewReader(`{"body":"edited"}`))
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		c, _ := db.GetCommentByID(0)
+		assert.Equal(t, "edited", c.Body)
+
+		res = do(h.UpdateComment, "PUT", map[string]string{"id": "0", "commentId": "99"}, "john", strings.NewReader(`{"body":"edited"}`))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Comments of trashed tasks are not reachable", func(t *testing.T) {
+		db.DeleteTask(0)
+		res := do(h.GetTaskComments, "GET", task, "", nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		db.RestoreTask(0)
+	})
+
+	t.Run("Delete comment", func(t *testing.T) {
+		res := do(h.DeleteComment, "DELETE", comment, "jane", nil)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+
+		res = do(h.DeleteComment, "DELETE", comment, "john", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, db.GetTaskComments(0))
+	})
+	t.Run("Comments without repository", func(t *testing.T) {
+		h := NewBaseHandler(db)
+		res := do(h.GetTaskComments, "GET", task, "", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.CreateComment, "POST", task, "john", strings.NewReader(`{"body":"hello"}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.UpdateComment, "PUT", comment, "john", strings.NewReader(`{"body":"edited"}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.DeleteComment, "DELETE", comment, "john", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+	})
+}
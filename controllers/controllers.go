@@ -2,9 +2,12 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"sync/atomic"
+	"todo-go/markdown"
 	"todo-go/models"
 
 	"github.com/gorilla/mux"
@@ -12,18 +15,75 @@ import (
 
 // BaseHandler will hold everything that controller needs
 type BaseHandler struct {
-	taskRepo models.TaskRepository
+	taskRepo    models.TaskRepository
+	historyRepo models.HistoryRepository
+	commentRepo models.CommentRepository
+	projectRepo models.ProjectRepository
+	worklogRepo models.WorklogRepository
+	linkRepo    models.LinkRepository
+	searchRepo  models.SearchRepository
+	viewRepo    models.ViewRepository
+	statsRepo   models.StatsRepository
+	workflow    *models.Workflow
+	linkTypes   models.LinkTypes
+	markdown    *markdown.Renderer
+
+	blobStore          models.BlobStore
+	maxAttachmentSize  int64
+	allowedAttachments []string
+
+	maxPositionLength int
+	wipLimits         map[models.Status]int
+
+	checklistAutoDone bool
+
+	// Set when the service shuts down
+	draining atomic.Bool
+}
+
+// Option configures optional dependencies of a BaseHandler
+type Option func(*BaseHandler)
+
+// WithWorkflow sets the workflow enforced on task statuses.
+// The default workflow is used if none is given.
+func WithWorkflow(wf *models.Workflow) Option {
+	return func(h *BaseHandler) {
+		h.workflow = wf
+	}
 }
 
 // NewBaseHandler returns a new BaseHandler
-func NewBaseHandler(taskRepo models.TaskRepository) *BaseHandler {
-	return &BaseHandler{
-		taskRepo: taskRepo,
+func NewBaseHandler(taskRepo models.TaskRepository, opts ...Option) *BaseHandler {
+	h := &BaseHandler{
+		taskRepo:          taskRepo,
+		workflow:          models.DefaultWorkflow(),
+		linkTypes:         models.DefaultLinkTypes(),
+		markdown:          markdown.NewRenderer(markdown.DefaultTaskURL, markdown.DefaultMentionURL),
+		maxPositionLength: defaultMaxPositionLength,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
+// tasks returns the task repository bound to the context of the request,
+// if the repository supports it
+func (h *BaseHandler) tasks(r *http.Request) models.TaskRepository {
+	if repo, ok := h.taskRepo.(models.ContextualTaskRepository); ok {
+		return repo.WithContext(r.Context())
+	}
+	return h.taskRepo
+}
+
+// uintVar returns the route variable key parsed to uint64
+func uintVar(r *http.Request, key string) (uint64, error) {
+	return strconv.ParseUint(mux.Vars(r)[key], 0, 0)
+}
+
+// RootHandler returns the tasks by id. Like GetTasks, it hides the tasks of archived projects.
 func (h *BaseHandler) RootHandler(w http.ResponseWriter, r *http.Request) {
-	t := h.taskRepo.GetAllTasks()
+	t := h.tasks(r).FindTasks(models.TaskFilter{Sort: []models.SortKey{{Field: "id"}}})
 	resp, err := json.Marshal(t)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -35,8 +95,23 @@ func (h *BaseHandler) RootHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(resp)
 }
 
+// GetTasks returns the tasks filtered by the query string parameters.
+// Tasks of archived projects are hidden unless archived=true.
+// render=html adds the HTML rendering of their body.
 func (h *BaseHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
-	t := h.taskRepo.GetAllTasks()
+	f, err := taskFilterFromQuery(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	t := h.tasks(r).FindTasks(f)
+	if err := h.renderTasks(r, t); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
 	resp, err := json.Marshal(t)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -63,19 +138,47 @@ func (h *BaseHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := h.taskRepo.CreateTask(t)
+	// New tasks without status start in the initial status of the workflow
+	if t.Status == "" {
+		t.Status = h.workflow.Initial
+	}
+	if !h.workflow.IsValid(t.Status) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("unknown status %s", t.Status)))
+		return
+	}
+	if t.EstimateMinutes < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("estimate_minutes must not be negative"))
+		return
+	}
+	if err := h.validateProject(&t); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	id, err := h.tasks(r).CreateTask(t, h.taskCondition(nil, t.Status))
+	if conditionFailed(err) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(err.Error()))
 		return
 	}
 
-	n, err := h.taskRepo.GetTaskByID(id)
+	n, err := h.tasks(r).GetTaskByID(id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(err.Error()))
 		return
 	}
+	h.recordHistory(r, models.HistoryActionCreate, id, nil, n)
+	h.rebalancePositions(n.Position)
+
 	resp, err := json.Marshal(n)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -99,7 +202,7 @@ func (h *BaseHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Lookup for the task with the requested id
-	t, err := h.taskRepo.GetTaskByID(id)
+	t, err := h.tasks(r).GetTaskByID(id)
 	if err != nil {
 		// If no task with the given id exists, respond 404
 		w.WriteHeader(http.StatusNotFound)
@@ -107,7 +210,15 @@ func (h *BaseHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := json.Marshal(t)
+	// Render a copy of the task, not the one held by the repository
+	tasks := []models.Task{*t}
+	if err := h.renderTasks(r, tasks); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	resp, err := json.Marshal(tasks[0])
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(err.Error()))
@@ -142,11 +253,56 @@ func (h *BaseHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	}
 	t.Id = id
 
-	if err := h.taskRepo.UpdateTask(t); err != nil {
+	current, err := h.tasks(r).GetTaskByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	// Enforce the workflow on status changes. A missing status keeps the current one.
+	if t.Status == "" {
+		t.Status = current.Status
+	}
+	if !h.workflow.IsValid(t.Status) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(fmt.Sprintf("unknown status %s", t.Status)))
+		return
+	}
+	// Tasks whose status isn't part of the workflow anymore can move to any valid status
+	if h.workflow.IsValid(current.Status) && !h.workflow.CanTransition(current.Status, t.Status) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(fmt.Sprintf("transition from %s to %s is not allowed", current.Status, t.Status)))
+		return
+	}
+	if t.EstimateMinutes < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("estimate_minutes must not be negative"))
+		return
+	}
+	if err := h.validateProject(&t); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	// Keep a copy of the task before the update for the history
+	old := *current
+	// The transition was checked against the current status, the update fails if it changed meanwhile
+	err = h.tasks(r).UpdateTask(t, h.taskCondition(current, t.Status))
+	if conditionFailed(err) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(err.Error()))
 		return
 	}
+	if n, err := h.tasks(r).GetTaskByID(id); err == nil {
+		h.recordHistory(r, models.HistoryActionUpdate, id, &old, n)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 	w.Write([]byte("resource updated successfully"))
@@ -162,9 +318,31 @@ func (h *BaseHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.taskRepo.DeleteTask(id); err != nil {
+	// ?hard=true permanently removes the task instead of moving it to the trash.
+	// It also applies to tasks already in the trash.
+	if hard, _ := strconv.ParseBool(r.URL.Query().Get("hard")); hard {
+		t, err := h.tasks(r).PurgeTask(id)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+			return
+		}
+		h.removeAttachmentBlobs(t)
+		h.recordHistory(r, models.HistoryActionPurge, id, nil, nil)
+		return
+	}
+
+	// Keep a copy of the task before the deletion for the history
+	var old *models.Task
+	if t, err := h.tasks(r).GetTaskByID(id); err == nil {
+		c := *t
+		old = &c
+	}
+
+	if err := h.tasks(r).DeleteTask(id); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(err.Error()))
 		return
 	}
+	h.recordHistory(r, models.HistoryActionDelete, id, old, nil)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"todo-go/databases"
@@ -13,8 +14,10 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// MockTaskRepository overrides the TaskRepository methods used by the tests.
+// Calling any other method panics.
 type MockTaskRepository struct {
-	taskRepo databases.InMemoryDatabase
+	models.TaskRepository
 }
 
 func (m MockTaskRepository) GetAllTasks() []models.Task {
@@ -27,11 +30,15 @@ func (m MockTaskRepository) GetAllTasks() []models.Task {
 	}
 }
 
-func (m MockTaskRepository) CreateTask(t models.Task) (uint64, error) {
+func (m MockTaskRepository) FindTasks(f models.TaskFilter) []models.Task {
+	return m.GetAllTasks()
+}
+
+func (m MockTaskRepository) CreateTask(t models.Task, c models.TaskCondition) (uint64, error) {
 	return uint64(1), nil
 }
 
-func (m MockTaskRepository) UpdateTask(t models.Task) error {
+func (m MockTaskRepository) UpdateTask(t models.Task, c models.TaskCondition) error {
 	return nil
 }
 
@@ -58,7 +65,7 @@ func TestNewBaseHandler(t *testing.T) {
 
 func TestRootHandler(t *testing.T) {
 	t.Run("Get a response", func(t *testing.T) {
-		h := &BaseHandler{&m}
+		h := NewBaseHandler(&m)
 		req, _ := http.NewRequest("GET", "/", nil)
 		res := httptest.NewRecorder()
 
@@ -67,11 +74,31 @@ func TestRootHandler(t *testing.T) {
 		assert.GreaterOrEqual(t, res.Body.Len(), 1)
 		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
 	})
+
+	t.Run("Hide tasks of archived projects", func(t *testing.T) {
+		db := databases.NewInMemoryDatabase()
+		archived, _ := db.CreateProject(models.Project{Name: "archived"})
+		db.UpdateProject(models.Project{Id: archived, Name: "archived", Archived: true})
+		db.CreateTask(models.Task{Title: "title 0"}, models.TaskCondition{})
+		db.CreateTask(models.Task{Title: "title 1", ProjectId: &archived}, models.TaskCondition{})
+		db.CreateTask(models.Task{Title: "title 2"}, models.TaskCondition{})
+
+		h := NewBaseHandler(db)
+		req, _ := http.NewRequest("GET", "/", nil)
+		res := httptest.NewRecorder()
+		h.RootHandler(res, req)
+
+		var tasks []models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 2)
+		assert.Equal(t, "title 0", tasks[0].Title)
+		assert.Equal(t, "title 2", tasks[1].Title)
+	})
 }
 
 func TestGetTasks(t *testing.T) {
 	t.Run("Get all tasks", func(t *testing.T) {
-		h := &BaseHandler{&m}
+		h := NewBaseHandler(&m)
 		req, _ := http.NewRequest("GET", "/", nil)
 		res := httptest.NewRecorder()
 
@@ -93,7 +120,7 @@ func TestGetTasks(t *testing.T) {
 
 func TestCreateTask(t *testing.T) {
 	t.Run("Create task without id", func(t *testing.T) {
-		h := &BaseHandler{&m}
+		h := NewBaseHandler(&m)
 		req, _ := http.NewRequest("POST", "/task", strings.NewReader(`{"title":"new title","body":"new body","priority":0,"status":"TODO"}`))
 		res := httptest.NewRecorder()
 
@@ -108,7 +135,7 @@ func TestCreateTask(t *testing.T) {
 	})
 
 	t.Run("Create task with id", func(t *testing.T) {
-		h := &BaseHandler{&m}
+		h := NewBaseHandler(&m)
 		req, _ := http.NewRequest("POST", "/task", strings.NewReader(`{"id": 100, "title":"new title","body":"new body","priority":0,"status":"TODO"}`))
 		res := httptest.NewRecorder()
 
@@ -123,7 +150,7 @@ func TestCreateTask(t *testing.T) {
 	})
 
 	t.Run("Create invalid task", func(t *testing.T) {
-		h := &BaseHandler{&m}
+		h := NewBaseHandler(&m)
 		req, _ := http.NewRequest("POST", "/task", strings.NewReader(`{"invalid": 121, "eded": eded}`))
 		res := httptest.NewRecorder()
 
@@ -137,7 +164,7 @@ func TestCreateTask(t *testing.T) {
 
 /*func TestGetTaskByID(t *testing.T) {
 	t.Run("Get a task with existing id", func(t *testing.T) {
-		h := &BaseHandler{&m}
+		h := NewBaseHandler(&m)
 
 		id := uint64(1)
 		req, _ := http.NewRequest("GET", fmt.Sprintf("/task/%d", 1), nil)
@@ -150,3 +177,35 @@ func TestCreateTask(t *testing.T) {
 		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
 	})
 }*/
+
+func TestGetTasksFilter(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db)
+	db.CreateTask(models.Task{Title: "first", Status: models.StatusToDo, Priority: models.High}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "second", Status: models.StatusDone, Priority: models.High}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "third", Status: models.StatusToDo, Priority: models.Low}, models.TaskCondition{})
+
+	get := func(filter string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/tasks", nil)
+		req.URL.RawQuery = url.Values{"filter": {filter}}.Encode()
+		res := httptest.NewRecorder()
+		h.GetTasks(res, req)
+		return res
+	}
+
+	t.Run("Filter tasks", func(t *testing.T) {
+		res := get(`status:TODO priority>=high OR title:second`)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var tasks []models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 2)
+		assert.Equal(t, "first", tasks[0].Title)
+		assert.Equal(t, "second", tasks[1].Title)
+	})
+
+	t.Run("Filter with invalid expression", func(t *testing.T) {
+		res := get(`status:TODO (priority>=high`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, "invalid filter: position 13: missing closing parenthesis", res.Body.String())
+	})
+}
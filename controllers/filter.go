@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"todo-go/models"
+)
+
+// taskFilterFromQuery builds a TaskFilter from the query string parameters:
+//   - status: comma separated list of statuses, can be repeated
+//   - priority: priority of the tasks
+//   - project: id of the project of the tasks
+//   - archived: "true" to include the tasks of archived projects
+//   - cf.<name>: value of the custom field <name>
+//   - filter: filter expression, see models.ParseTaskQuery
+//   - sort: comma separated list of fields to sort on, prefixed by "-" for descending order
+func taskFilterFromQuery(q url.Values) (models.TaskFilter, error) {
+	var f models.TaskFilter
+
+	for _, v := range q["status"] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				f.Statuses = append(f.Statuses, models.Status(s))
+			}
+		}
+	}
+
+	if v := q.Get("priority"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return f, fmt.Errorf("invalid priority %s", v)
+		}
+		priority := models.Priority(p)
+		f.Priority = &priority
+	}
+
+	if v := q.Get("project"); v != "" {
+		id, err := strconv.ParseUint(v, 0, 0)
+		if err != nil {
+			return f, fmt.Errorf("invalid project %s", v)
+		}
+		f.ProjectId = &id
+	}
+
+	if v := q.Get("archived"); v != "" {
+		archived, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, fmt.Errorf("invalid archived %s", v)
+		}
+		f.IncludeArchived = archived
+	}
+
+	for k, v := range q {
+		if name := strings.TrimPrefix(k, models.CustomFieldPrefix); name != k && name != "" {
+			if f.CustomFields == nil {
+				f.CustomFields = make(map[string]string)
+			}
+			f.CustomFields[name] = v[0]
+		}
+	}
+
+	if v := q.Get("filter"); v != "" {
+		e, err := models.ParseTaskQuery(v)
+		if err != nil {
+			return f, fmt.Errorf("invalid filter: %w", err)
+		}
+		f.Query = e
+	}
+
+	if v := q.Get("sort"); v != "" {
+		keys, err := models.ParseSortKeys(v)
+		if err != nil {
+			return f, err
+		}
+		f.Sort = keys
+	}
+
+	return f, nil
+}
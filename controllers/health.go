@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+	"todo-go/models"
+)
+
+// Maximum time given to the components to report their health
+const healthCheckTimeout = 2 * time.Second
+
+// StartDraining makes the readiness probe fail so the orchestrator stops
+// routing requests to the service before it shuts down
+func (h *BaseHandler) StartDraining() {
+	h.draining.Store(true)
+}
+
+func writeHealthReport(w http.ResponseWriter, report *models.HealthReport) {
+	resp, err := json.Marshal(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != models.HealthStatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(resp)
+}
+
+// Healthz is the liveness probe: it succeeds as long as the process serves requests
+func (h *BaseHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, models.NewHealthReport(nil))
+}
+
+// Readyz is the readiness probe: it checks the components the service depends on
+// and fails while the service shuts down
+func (h *BaseHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	components := map[string]models.ComponentHealth{
+		"server": {Status: models.HealthStatusOK},
+	}
+	if h.draining.Load() {
+		components["server"] = models.ComponentHealth{Status: models.HealthStatusUnavailable, Error: "shutting down"}
+	}
+
+	if err := h.taskRepo.HealthCheck(ctx); err != nil {
+		components["database"] = models.ComponentHealth{Status: models.HealthStatusUnavailable, Error: err.Error()}
+	} else {
+		components["database"] = models.ComponentHealth{Status: models.HealthStatusOK}
+	}
+
+	writeHealthReport(w, models.NewHealthReport(components))
+}
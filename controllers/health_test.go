@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unhealthyTaskRepository struct {
+	models.TaskRepository
+}
+
+func (m unhealthyTaskRepository) HealthCheck(ctx context.Context) error {
+	return fmt.Errorf("connection refused")
+}
+
+func TestHealth(t *testing.T) {
+	probe := func(handler http.HandlerFunc) (int, models.HealthReport) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		res := httptest.NewRecorder()
+		handler(res, req)
+		var report models.HealthReport
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &report))
+		return res.Code, report
+	}
+
+	h := NewBaseHandler(databases.NewInMemoryDatabase())
+
+	t.Run("Alive", func(t *testing.T) {
+		code, report := probe(h.Healthz)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.HealthStatusOK, report.Status)
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		code, report := probe(h.Readyz)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.HealthStatusOK, report.Status)
+		assert.Equal(t, models.HealthStatusOK, report.Components["database"].Status)
+	})
+
+	t.Run("Not ready with an unhealthy database", func(t *testing.T) {
+		code, report := probe(NewBaseHandler(unhealthyTaskRepository{}).Readyz)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, models.HealthStatusUnavailable, report.Status)
+		assert.Equal(t, models.ComponentHealth{Status: models.HealthStatusUnavailable, Error: "connection refused"}, report.Components["database"])
+		assert.Equal(t, models.HealthStatusOK, report.Components["server"].Status)
+	})
+
+	t.Run("Not ready while draining", func(t *testing.T) {
+		h.StartDraining()
+		code, report := probe(h.Readyz)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "shutting down", report.Components["server"].Error)
+
+		code, _ = probe(h.Healthz)
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
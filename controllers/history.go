@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// Header identifying the user performing the request
+	userHeader = "X-User"
+	// Header identifying the request
+	requestIDHeader = "X-Request-ID"
+	// User recorded when the request doesn't identify one
+	anonymousUser = "anonymous"
+	// User recorded for changes made outside of a request
+	systemUser = "system"
+)
+
+// WithHistoryRepository sets the repository recording the changes made to tasks
+func WithHistoryRepository(historyRepo models.HistoryRepository) Option {
+	return func(h *BaseHandler) {
+		h.historyRepo = historyRepo
+	}
+}
+
+// userFromRequest returns the user performing the request
+func userFromRequest(r *http.Request) string {
+	if u := r.Header.Get(userHeader); u != "" {
+		return u
+	}
+	return anonymousUser
+}
+
+// recordHistory records the change of a task made by the request.
+// old is nil for a creation and new is nil for a deletion.
+// r is nil for changes made outside of a request, like the trash purge.
+// Failing to record the change doesn't fail the request as the change is already done.
+func (h *BaseHandler) recordHistory(r *http.Request, action models.HistoryAction, taskID uint64, old, new *models.Task) {
+	if h.historyRepo == nil {
+		return
+	}
+
+	changes := models.DiffTasks(old, new)
+	if action == models.HistoryActionUpdate && len(changes) == 0 {
+		return
+	}
+
+	e := models.HistoryEntry{
+		TaskId:    taskID,
+		Action:    action,
+		Changes:   changes,
+		Actor:     systemUser,
+		Timestamp: time.Now(),
+	}
+	if r != nil {
+		e.Actor = userFromRequest(r)
+		e.RequestId = r.Header.Get(requestIDHeader)
+	}
+
+	_, err := h.historyRepo.AddHistoryEntry(e)
+	if err != nil {
+		log.Printf("failed to record history of task id %d: %s", taskID, err.Error())
+	}
+}
+
+func (h *BaseHandler) GetTaskHistory(w http.ResponseWriter, r *http.Request) {
+	// Get id from URL and parse it to uint64
+	muxID := mux.Vars(r)["id"]
+	id, err := strconv.ParseUint(muxID, 0, 0)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.historyRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("history is not available"))
+		return
+	}
+
+	// The history of deleted tasks is kept
+	entries := h.historyRepo.GetTaskHistory(id)
+	if len(entries) == 0 {
+		if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found"))
+			return
+		}
+	}
+
+	resp, err := json.Marshal(entries)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
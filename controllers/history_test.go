@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTaskHistory(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithHistoryRepository(db))
+
+	getHistory := func(id string) ([]models.HistoryEntry, *httptest.ResponseRecorder) {
+		req, _ := http.NewRequest("GET", "/task/"+id+"/history", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		res := httptest.NewRecorder()
+		h.GetTaskHistory(res, req)
+
+		var entries []models.HistoryEntry
+		json.Unmarshal(res.Body.Bytes(), &entries)
+		return entries, res
+	}
+
+	t.Run("Record create, update and delete", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/task", strings.NewReader(`{"title":"title","priority":1}`))
+		req.Header.Set("X-User", "john")
+		req.Header.Set("X-Request-ID", "abc")
+		h.CreateTask(httptest.NewRecorder(), req)
+
+		req, _ = http.NewRequest("PUT", "/task/0", strings.NewReader(`{"title":"title","priority":3}`))
+		req = mux.SetURLVars(req, map[string]string{"id": "0"})
+		req.Header.Set("X-User", "jane")
+		h.UpdateTask(httptest.NewRecorder(), req)
+
+		req, _ = http.NewRequest("DELETE", "/task/0", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "0"})
+		h.DeleteTask(httptest.NewRecorder(), req)
+
+		entries, res := getHistory("0")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
+		assert.Len(t, entries, 3)
+
+		assert.Equal(t, models.HistoryActionCreate, entries[0].Action)
+		assert.Equal(t, "john", entries[0].Actor)
+		assert.Equal(t, "abc", entries[0].RequestId)
+
+		assert.Equal(t, models.HistoryActionUpdate, entries[1].Action)
+		assert.Equal(t, "jane", entries[1].Actor)
+		assert.Len(t, entries[1].Changes, 1)
+		assert.Equal(t, "priority", entries[1].Changes[0].Field)
+		assert.Equal(t, float64(models.Low), entries[1].Changes[0].Old)
+		assert.Equal(t, float64(models.High), entries[1].Changes[0].New)
+
+		assert.Equal(t, models.HistoryActionDelete, entries[2].Action)
+		assert.Equal(t, anonymousUser, entries[2].Actor)
+	})
+
+	t.Run("Get history of non existing task", func(t *testing.T) {
+		_, res := getHistory("99")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
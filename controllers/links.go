@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"todo-go/models"
+)
+
+// WithLinkRepository sets the repository storing the links between tasks
+func WithLinkRepository(linkRepo models.LinkRepository) Option {
+	return func(h *BaseHandler) {
+		h.linkRepo = linkRepo
+	}
+}
+
+// WithLinkTypes sets the types tasks can be linked with.
+// The default link types are used if none are given.
+func WithLinkTypes(types models.LinkTypes) Option {
+	return func(h *BaseHandler) {
+		h.linkTypes = types
+	}
+}
+
+func (h *BaseHandler) GetTaskLinks(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.linkRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("links are not available"))
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	links := make([]models.TaskLink, 0)
+	for _, l := range h.linkRepo.GetTaskLinks(id) {
+		links = append(links, h.linkTypes.From(id, l))
+	}
+	resp, err := json.Marshal(links)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// CreateLink links the task to the task_id of the request body with the given type.
+// The type can be named from either side of the link, eg: "causes" or "caused_by".
+func (h *BaseHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.linkRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("links are not available"))
+		return
+	}
+
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	var tl models.TaskLink
+	if err := json.Unmarshal(rBody, &tl); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+	if _, err := h.tasks(r).GetTaskByID(tl.TaskId); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	l, err := h.linkTypes.NewLink(id, tl.TaskId, tl.Type)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	linkID, err := h.linkRepo.CreateLink(l)
+	if err != nil {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	n, err := h.linkRepo.GetLinkByID(linkID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	resp, err := json.Marshal(h.linkTypes.From(id, *n))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resp)
+}
+
+// DeleteLink removes a link of the task, from either side
+func (h *BaseHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	linkID, err := uintVar(r, "linkId")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.linkRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("links are not available"))
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+	l, err := h.linkRepo.GetLinkByID(linkID)
+	if err != nil || (l.SourceId != id && l.TargetId != id) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	if err := h.linkRepo.DeleteLink(l.Id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinks(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithLinkRepository(db))
+	for i := 0; i < 3; i++ {
+		db.CreateTask(models.Task{Title: "title"}, models.TaskCondition{})
+	}
+
+	do := func(handler http.HandlerFunc, method string, vars map[string]string, body io.Reader) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/", body)
+		req = mux.SetURLVars(req, vars)
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+	links := func(id string) []models.TaskLink {
+		res := do(h.GetTaskLinks, "GET", map[string]string{"id": id}, nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var l []models.TaskLink
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &l))
+		return l
+	}
+
+	t.Run("Create link", func(t *testing.T) {
+		res := do(h.CreateLink, "POST", map[string]string{"id": "0"}, strings.NewReader(`{"type":"causes","task_id":1}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
+		var l models.TaskLink
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &l))
+		assert.Equal(t, models.TaskLink{Id: 0, Type: "causes", TaskId: 1}, l)
+
+		assert.Equal(t, []models.TaskLink{{Id: 0, Type: "caused_by", TaskId: 0}}, links("1"))
+	})
+
+	t.Run("Create invalid links", func(t *testing.T) {
+		res := do(h.CreateLink, "POST", map[string]string{"id": "1"}, strings.NewReader(`{"type":"caused_by","task_id":0}`))
+		assert.Equal(t, http.StatusConflict, res.Code)
+		res = do(h.CreateLink, "POST", map[string]string{"id": "0"}, strings.NewReader(`{"type":"blocks","task_id":2}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = do(h.CreateLink, "POST", map[string]string{"id": "0"}, strings.NewReader(`{"type":"relates_to","task_id":0}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = do(h.CreateLink, "POST", map[string]string{"id": "0"}, strings.NewReader(`{"type":"relates_to","task_id":99}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = do(h.CreateLink, "POST", map[string]string{"id": "99"}, strings.NewReader(`{"type":"relates_to","task_id":0}`))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Links follow the trash", func(t *testing.T) {
+		db.DeleteTask(0)
+		assert.Empty(t, links("1"))
+		db.RestoreTask(0)
+		assert.Len(t, links("1"), 1)
+	})
+
+	t.Run("Delete link", func(t *testing.T) {
+		res := do(h.DeleteLink, "DELETE", map[string]string{"id": "2", "linkId": "0"}, nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		res = do(h.DeleteLink, "DELETE", map[string]string{"id": "1", "linkId": "0"}, nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, links("0"))
+	})
+
+	t.Run("Links without repository", func(t *testing.T) {
+		h := NewBaseHandler(db)
+		res := do(h.GetTaskLinks, "GET", map[string]string{"id": "0"}, nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.CreateLink, "POST", map[string]string{"id": "0"}, strings.NewReader(`{"type":"causes","task_id":1}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.DeleteLink, "DELETE", map[string]string{"id": "0", "linkId": "0"}, nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+	})
+}
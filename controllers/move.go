@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"todo-go/models"
+)
+
+// Default length of the positions above which they are rebalanced
+const defaultMaxPositionLength = 32
+
+// WithMaxPositionLength sets the length of the task positions above which
+// all the positions are rebalanced
+func WithMaxPositionLength(n int) Option {
+	return func(h *BaseHandler) {
+		h.maxPositionLength = n
+	}
+}
+
+// rebalancePositions rebalances the positions of the tasks
+// when the given position got too long
+func (h *BaseHandler) rebalancePositions(position string) {
+	if len(position) <= h.maxPositionLength {
+		return
+	}
+	if err := h.taskRepo.RebalancePositions(); err != nil {
+		log.Printf("failed to rebalance task positions: %s", err.Error())
+	}
+}
+
+// MoveTask moves a task right before or right after another task and,
+// if a status is given, to that status. The status change follows the
+// workflow and the work in progress limit of the new status.
+func (h *BaseHandler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	var m models.TaskMove
+	if err := json.Unmarshal(rBody, &m); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if m.Before != nil && m.After != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("only one of before and after can be set"))
+		return
+	}
+	if m.Before == nil && m.After == nil && m.Status == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("one of before, after and status must be set"))
+		return
+	}
+
+	current, err := h.tasks(r).GetTaskByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+	// Keep a copy of the task before the move for the history
+	old := *current
+
+	if m.Status != "" {
+		if !h.workflow.IsValid(m.Status) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("unknown status %s", m.Status)))
+			return
+		}
+		if h.workflow.IsValid(old.Status) && !h.workflow.CanTransition(old.Status, m.Status) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(fmt.Sprintf("transition from %s to %s is not allowed", old.Status, m.Status)))
+			return
+		}
+		// The transition was checked against the current status, the move fails if it changed meanwhile
+		m.Condition = h.taskCondition(&old, m.Status)
+	}
+
+	t, err := h.tasks(r).MoveTask(id, m)
+	if conditionFailed(err) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		// The other task doesn't exist
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	h.recordHistory(r, models.HistoryActionUpdate, id, &old, t)
+	h.rebalancePositions(t.Position)
+
+	// Positions may have changed with the rebalancing
+	if n, err := h.tasks(r).GetTaskByID(id); err == nil {
+		t = n
+	}
+	resp, err := json.Marshal(t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
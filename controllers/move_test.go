@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveTask(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithMaxPositionLength(4))
+	for i := 0; i < 3; i++ {
+		db.CreateTask(models.Task{}, models.TaskCondition{})
+	}
+
+	move := func(id, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/task/"+id+"/move", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		res := httptest.NewRecorder()
+		h.MoveTask(res, req)
+		return res
+	}
+	order := func() []uint64 {
+		req, _ := http.NewRequest("GET", "/tasks", nil)
+		res := httptest.NewRecorder()
+		h.GetTasks(res, req)
+		var tasks []models.Task
+		json.Unmarshal(res.Body.Bytes(), &tasks)
+		ids := make([]uint64, 0)
+		for _, t := range tasks {
+			ids = append(ids, t.Id)
+		}
+		return ids
+	}
+
+	t.Run("Move task", func(t *testing.T) {
+		res := move("2", `{"before":0}`)
+		var task models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &task))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, uint64(2), task.Id)
+		assert.Equal(t, []uint64{2, 0, 1}, order())
+
+		res = move("2", `{"after":1}`)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, []uint64{0, 1, 2}, order())
+	})
+
+	t.Run("Invalid moves", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, move("0", `{}`).Code)
+		assert.Equal(t, http.StatusBadRequest, move("0", `{"before":1,"after":2}`).Code)
+		assert.Equal(t, http.StatusBadRequest, move("0", `{"before":99}`).Code)
+		assert.Equal(t, http.StatusNotFound, move("99", `{"before":0}`).Code)
+	})
+
+	t.Run("Long positions are rebalanced", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			move("2", `{"after":1}`)
+			move("1", `{"after":2}`)
+		}
+		for _, task := range db.GetAllTasks() {
+			assert.LessOrEqual(t, len(task.Position), 4)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"todo-go/models"
+)
+
+// WithProjectRepository sets the repository storing the projects
+func WithProjectRepository(projectRepo models.ProjectRepository) Option {
+	return func(h *BaseHandler) {
+		h.projectRepo = projectRepo
+	}
+}
+
+// validateProject checks the project of the task exists and the custom fields
+// of the task match the schema of the project. Custom field values are normalized.
+func (h *BaseHandler) validateProject(t *models.Task) error {
+	if t.ProjectId == nil {
+		if len(t.CustomFields) > 0 {
+			return fmt.Errorf("custom fields can only be set on tasks of a project")
+		}
+		return nil
+	}
+	if h.projectRepo == nil {
+		return fmt.Errorf("projects are not available")
+	}
+	p, err := h.projectRepo.GetProjectByID(*t.ProjectId)
+	if err != nil {
+		return fmt.Errorf("unknown project %d", *t.ProjectId)
+	}
+
+	values, err := models.ValidateCustomFields(p.CustomFields, t.CustomFields)
+	if err != nil {
+		return err
+	}
+	t.CustomFields = values
+	return nil
+}
+
+// decodeProject reads the project sent in the request body
+func decodeProject(w http.ResponseWriter, r *http.Request) *models.Project {
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	var p models.Project
+	if err := json.Unmarshal(rBody, &p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("project name must not be empty"))
+		return nil
+	}
+	if err := models.ValidateCustomFieldsSchema(p.CustomFields); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	return &p
+}
+
+func (h *BaseHandler) GetProjects(w http.ResponseWriter, r *http.Request) {
+	if h.projectRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("projects are not available"))
+		return
+	}
+
+	resp, err := json.Marshal(h.projectRepo.GetAllProjects())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+func (h *BaseHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	if h.projectRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("projects are not available"))
+		return
+	}
+
+	p := decodeProject(w, r)
+	if p == nil {
+		return
+	}
+
+	id, err := h.projectRepo.CreateProject(*p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	n, err := h.projectRepo.GetProjectByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	resp, err := json.Marshal(n)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resp)
+}
+
+func (h *BaseHandler) GetProjectByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.projectRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("projects are not available"))
+		return
+	}
+
+	p, err := h.projectRepo.GetProjectByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	resp, err := json.Marshal(p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// UpdateProject replaces a project. Setting "archived" to true archives the project.
+func (h *BaseHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.projectRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("projects are not available"))
+		return
+	}
+
+	p := decodeProject(w, r)
+	if p == nil {
+		return
+	}
+
+	p.Id = id
+	err = h.projectRepo.UpdateProject(*p)
+	if errors.Is(err, models.ErrCustomFieldsInUse) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BaseHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.projectRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("projects are not available"))
+		return
+	}
+
+	if _, err := h.projectRepo.GetProjectByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	if err := h.projectRepo.DeleteProject(id); err != nil {
+		// The project still holds tasks
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+		return
+	}
+}
+
+// GetProjectTasks returns the tasks of a project, archived or not,
+// with the same filtering as GetTasks
+func (h *BaseHandler) GetProjectTasks(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.projectRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("projects are not available"))
+		return
+	}
+
+	if _, err := h.projectRepo.GetProjectByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	f, err := taskFilterFromQuery(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	f.ProjectId = &id
+	f.IncludeArchived = true
+
+	t := h.tasks(r).FindTasks(f)
+	if err := h.renderTasks(r, t); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	resp, err := json.Marshal(t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
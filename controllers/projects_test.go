@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjects(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithProjectRepository(db))
+
+	do := func(handler http.HandlerFunc, method, url string, vars map[string]string, body io.Reader) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, body)
+		req = mux.SetURLVars(req, vars)
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+	project := map[string]string{"id": "0"}
+
+	t.Run("Create project", func(t *testing.T) {
+		res := do(h.CreateProject, "POST", "/projects", nil, strings.NewReader(`{"name":"backend"}`))
+		var p models.Project
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &p))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, "backend", p.Name)
+
+		res = do(h.CreateProject, "POST", "/projects", nil, strings.NewReader(`{"name":""}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Get projects", func(t *testing.T) {
+		res := do(h.GetProjects, "GET", "/projects", nil, nil)
+		var projects []models.Project
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &projects))
+		assert.Len(t, projects, 1)
+
+		res = do(h.GetProjectByID, "GET", "/projects/0", project, nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		res = do(h.GetProjectByID, "GET", "/projects/99", map[string]string{"id": "99"}, nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Create tasks in projects", func(t *testing.T) {
+		res := do(h.CreateTask, "POST", "/task", nil, strings.NewReader(`{"title":"in project","project_id":0}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		res = do(h.CreateTask, "POST", "/task", nil, strings.NewReader(`{"title":"in project","project_id":0,"status":"INPROGRESS"}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		res = do(h.CreateTask, "POST", "/task", nil, strings.NewReader(`{"title":"global"}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+
+		res = do(h.CreateTask, "POST", "/task", nil, strings.NewReader(`{"title":"unknown project","project_id":99}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Get project tasks", func(t *testing.T) {
+		res := do(h.GetProjectTasks, "GET", "/projects/0/tasks", project, nil)
+		var tasks []models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Len(t, tasks, 2)
+
+		res = do(h.GetProjectTasks, "GET", "/projects/0/tasks?status=INPROGRESS", project, nil)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 1)
+
+		res = do(h.GetProjectTasks, "GET", "/projects/0/tasks?priority=high", project, nil)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Archived project tasks are hidden", func(t *testing.T) {
+		res := do(h.UpdateProject, "PUT", "/projects/0", project, strings.NewReader(`{"name":"backend","archived":true}`))
+		assert.Equal(t, http.StatusNoContent, res.Code)
+
+		var tasks []models.Task
+		res = do(h.GetTasks, "GET", "/tasks", nil, nil)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 1)
+
+		res = do(h.GetTasks, "GET", "/tasks?archived=true", nil, nil)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 3)
+
+		res = do(h.GetProjectTasks, "GET", "/projects/0/tasks", project, nil)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 2)
+	})
+
+	t.Run("Delete project", func(t *testing.T) {
+		res := do(h.DeleteProject, "DELETE", "/projects/0", project, nil)
+		assert.Equal(t, http.StatusConflict, res.Code)
+
+		do(h.CreateProject, "POST", "/projects", nil, strings.NewReader(`{"name":"empty"}`))
+		res = do(h.DeleteProject, "DELETE", "/projects/1", map[string]string{"id": "1"}, nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		res = do(h.DeleteProject, "DELETE", "/projects/1", map[string]string{"id": "1"}, nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Projects without repository", func(t *testing.T) {
+		h := NewBaseHandler(db)
+		res := do(h.GetProjects, "GET", "/projects", nil, nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.CreateProject, "POST", "/projects", nil, strings.NewReader(`{"name":"backend"}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.GetProjectByID, "GET", "/projects/0", project, nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.UpdateProject, "PUT", "/projects/0", project, strings.NewReader(`{"name":"backend"}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.DeleteProject, "DELETE", "/projects/0", project, nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.GetProjectTasks, "GET", "/projects/0/tasks", project, nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+	})
+}
+
+func TestCustomFields(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithProjectRepository(db))
+
+	create := func(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+
+	t.Run("Create project with custom fields", func(t *testing.T) {
+		res := create(h.CreateProject, `{"name":"backend","custom_fields":[{"name":"points","type":"number"},{"name":"sprint","type":"enum","options":["S1","S2"]}]}`)
+		assert.Equal(t, http.StatusCreated, res.Code)
+
+		res = create(h.CreateProject, `{"name":"invalid","custom_fields":[{"name":"points","type":"float"}]}`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Create tasks with custom fields", func(t *testing.T) {
+		res := create(h.CreateTask, `{"title":"a","project_id":0,"custom_fields":{"points":5,"sprint":"S1"}}`)
+		assert.Equal(t, http.StatusCreated, res.Code)
+		res = create(h.CreateTask, `{"title":"b","project_id":0,"custom_fields":{"points":2,"sprint":"S2"}}`)
+		assert.Equal(t, http.StatusCreated, res.Code)
+
+		res = create(h.CreateTask, `{"title":"c","project_id":0,"custom_fields":{"points":"many"}}`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = create(h.CreateTask, `{"title":"d","project_id":0,"custom_fields":{"sprint":"S3"}}`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = create(h.CreateTask, `{"title":"e","custom_fields":{"points":1}}`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Filter and sort on custom fields", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/tasks?cf.sprint=S1", nil)
+		res := httptest.NewRecorder()
+		h.GetTasks(res, req)
+		var tasks []models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 1)
+		assert.Equal(t, "a", tasks[0].Title)
+
+		req, _ = http.NewRequest("GET", "/tasks?sort=cf.points", nil)
+		res = httptest.NewRecorder()
+		h.GetTasks(res, req)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Equal(t, "b", tasks[0].Title)
+		assert.Equal(t, "a", tasks[1].Title)
+
+		req, _ = http.NewRequest("GET", "/tasks?sort=body", nil)
+		res = httptest.NewRecorder()
+		h.GetTasks(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+	t.Run("Change custom fields in use", func(t *testing.T) {
+		update := func(body string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("PUT", "/projects/0", strings.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": "0"})
+			res := httptest.NewRecorder()
+			h.UpdateProject(res, req)
+			return res
+		}
+
+		// Tasks hold S1 and S2, and numbers in points
+		res := update(`{"name":"backend","custom_fields":[{"name":"points","type":"number"},{"name":"sprint","type":"enum","options":["S2","S3"]}]}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		res = update(`{"name":"backend","custom_fields":[{"name":"points","type":"string"},{"name":"sprint","type":"enum","options":["S1","S2"]}]}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		res = update(`{"name":"backend","custom_fields":[{"name":"points","type":"number"}]}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		res = update(`{"name":"backend","custom_fields":[{"name":"points","type":"number"},{"name":"sprint","type":"enum","options":["S1","S2"]},{"name":"team","type":"string","required":true}]}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+
+		p, _ := db.GetProjectByID(0)
+		assert.Len(t, p.CustomFields, 2)
+
+		res = update(`{"name":"backend","custom_fields":[{"name":"points","type":"number","required":true},{"name":"sprint","type":"enum","options":["S1","S2","S3"]},{"name":"team","type":"string"}]}`)
+		assert.Equal(t, http.StatusNoContent, res.Code)
+	})
+}
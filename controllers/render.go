@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"todo-go/markdown"
+	"todo-go/models"
+)
+
+// Format of the rendering of task bodies requested with render=html
+const renderHTML = "html"
+
+// WithMarkdownRenderer sets the renderer of task bodies.
+// A renderer with the default links is used if none is given.
+func WithMarkdownRenderer(renderer *markdown.Renderer) Option {
+	return func(h *BaseHandler) {
+		h.markdown = renderer
+	}
+}
+
+// renderTasks fills the body_html of the tasks if the request asks for it with render=html.
+// The tasks must be copies, not the tasks held by the repository.
+func (h *BaseHandler) renderTasks(r *http.Request, tasks []models.Task) error {
+	switch f := r.URL.Query().Get("render"); f {
+	case "":
+		return nil
+	case renderHTML:
+	default:
+		return fmt.Errorf("unknown render format %s: must be %s", f, renderHTML)
+	}
+
+	for k := range tasks {
+		html, err := h.markdown.Render(tasks[k].Body)
+		if err != nil {
+			return err
+		}
+		tasks[k].BodyHTML = html
+	}
+	return nil
+}
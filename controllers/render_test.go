@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTasks(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db)
+	db.CreateTask(models.Task{Title: "title", Body: "See #1 <script>alert(1)</script>"}, models.TaskCondition{})
+
+	get := func(handler http.HandlerFunc, target string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", target, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "0"})
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+	html := `<p>See <a href="/task/1" class="task-ref" rel="nofollow">#1</a> alert(1)</p>` + "\n"
+
+	t.Run("Render task body", func(t *testing.T) {
+		res := get(h.GetTaskByID, "/task/0?render=html")
+		assert.Equal(t, http.StatusOK, res.Code)
+		var task models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &task))
+		assert.Equal(t, html, task.BodyHTML)
+
+		// The stored task is left untouched
+		stored, _ := db.GetTaskByID(0)
+		assert.Empty(t, stored.BodyHTML)
+	})
+
+	t.Run("Render task bodies", func(t *testing.T) {
+		res := get(h.GetTasks, "/tasks?render=html")
+		assert.Equal(t, http.StatusOK, res.Code)
+		var tasks []models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Equal(t, html, tasks[0].BodyHTML)
+	})
+
+	t.Run("Don't render by default", func(t *testing.T) {
+		res := get(h.GetTaskByID, "/task/0")
+		assert.NotContains(t, res.Body.String(), "body_html")
+	})
+
+	t.Run("Render unknown format", func(t *testing.T) {
+		res := get(h.GetTasks, "/tasks?render=pdf")
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = get(h.GetTaskByID, "/task/0?render=pdf")
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
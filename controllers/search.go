@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"todo-go/models"
+	"todo-go/search"
+)
+
+const (
+	// Number of search results returned when the request doesn't give a limit
+	defaultSearchLimit = 20
+	// Maximum number of search results returned
+	maxSearchLimit = 100
+)
+
+// WithSearchRepository sets the repository searching the title and body of tasks
+func WithSearchRepository(searchRepo models.SearchRepository) Option {
+	return func(h *BaseHandler) {
+		h.searchRepo = searchRepo
+	}
+}
+
+// SearchTasks returns the tasks matching the full-text query q, the most relevant first.
+// See search.ParseQuery for the syntax of queries.
+func (h *BaseHandler) SearchTasks(w http.ResponseWriter, r *http.Request) {
+	if h.searchRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("search is not available"))
+		return
+	}
+
+	q, err := search.ParseQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	limit := defaultSearchLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("invalid limit %s: must be between 1 and %d", v, maxSearchLimit)))
+			return
+		}
+	}
+
+	resp, err := json.Marshal(h.searchRepo.SearchTasks(q, limit))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
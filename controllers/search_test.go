@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTasks(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithSearchRepository(db))
+	db.CreateTask(models.Task{Title: "Login page", Body: "Crashes on mobile"}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "Release notes", Body: "Mention the login fix"}, models.TaskCondition{})
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", target, nil)
+		res := httptest.NewRecorder()
+		h.SearchTasks(res, req)
+		return res
+	}
+
+	t.Run("Search tasks", func(t *testing.T) {
+		res := get(`/search?q=logins`)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
+		var results []models.SearchResult
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &results))
+		assert.Len(t, results, 2)
+		assert.Equal(t, "Login page", results[0].Task.Title)
+		assert.Greater(t, results[0].Score, results[1].Score)
+
+		res = get(`/search?q=%22login+fix%22&limit=1`)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &results))
+		assert.Len(t, results, 1)
+		assert.Equal(t, "Release notes", results[0].Task.Title)
+
+		res = get(`/search?q=unknown`)
+		assert.Equal(t, "[]", res.Body.String())
+	})
+
+	t.Run("Search invalid queries", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get(`/search`).Code)
+		assert.Equal(t, http.StatusBadRequest, get(`/search?q=%22login`).Code)
+		assert.Equal(t, http.StatusBadRequest, get(`/search?q=login&limit=0`).Code)
+		assert.Equal(t, http.StatusBadRequest, get(`/search?q=login&limit=1000`).Code)
+	})
+	t.Run("Search without repository", func(t *testing.T) {
+		h := NewBaseHandler(db)
+		req, _ := http.NewRequest("GET", "/search?q=login", nil)
+		res := httptest.NewRecorder()
+		h.SearchTasks(res, req)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+	})
+}
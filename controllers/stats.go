@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"todo-go/models"
+)
+
+const (
+	// Number of days of the statistics when the range isn't given
+	defaultStatsDays = 30
+	// Maximum number of days of the statistics
+	maxStatsDays = 366
+)
+
+// WithStatsRepository sets the repository computing the statistics of tasks
+func WithStatsRepository(statsRepo models.StatsRepository) Option {
+	return func(h *BaseHandler) {
+		h.statsRepo = statsRepo
+	}
+}
+
+// statsFilterFromQuery parses the range of days and the project of the statistics.
+// The range defaults to the last 30 days, today included.
+func statsFilterFromQuery(q url.Values, now time.Time) (models.StatsFilter, error) {
+	var f models.StatsFilter
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	f.To = today.AddDate(0, 0, 1)
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid to %s: must be formatted as %s", v, time.DateOnly)
+		}
+		f.To = to.AddDate(0, 0, 1)
+	}
+
+	f.From = f.To.AddDate(0, 0, -defaultStatsDays)
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid from %s: must be formatted as %s", v, time.DateOnly)
+		}
+		f.From = from
+	}
+
+	if !f.From.Before(f.To) {
+		return f, fmt.Errorf("from must not be after to")
+	}
+	if f.From.AddDate(0, 0, maxStatsDays).Before(f.To) {
+		return f, fmt.Errorf("range must not exceed %d days", maxStatsDays)
+	}
+
+	if v := q.Get("project"); v != "" {
+		id, err := strconv.ParseUint(v, 0, 0)
+		if err != nil {
+			return f, fmt.Errorf("invalid project %s", v)
+		}
+		f.ProjectId = &id
+	}
+
+	return f, nil
+}
+
+// GetStats returns the statistics of the tasks over the range of days given by
+// the from and to query string parameters, optionally restricted to a project.
+func (h *BaseHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	if h.statsRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("statistics are not available"))
+		return
+	}
+
+	f, err := statsFilterFromQuery(r.URL.Query(), time.Now().UTC())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	resp, err := json.Marshal(h.statsRepo.GetStats(f))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsFilterFromQuery(t *testing.T) {
+	now := time.Date(2024, 3, 15, 18, 30, 0, 0, time.UTC)
+
+	f, err := statsFilterFromQuery(url.Values{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), f.From)
+	assert.Equal(t, time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC), f.To)
+	assert.Len(t, f.Days(), 30)
+
+	f, err = statsFilterFromQuery(url.Values{"from": {"2024-01-01"}, "to": {"2024-01-07"}, "project": {"2"}}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-01-01", "2024-01-02", "2024-01-03", "2024-01-04", "2024-01-05", "2024-01-06", "2024-01-07"}, f.Days())
+	assert.Equal(t, uint64(2), *f.ProjectId)
+
+	for _, q := range []url.Values{
+		{"from": {"01/01/2024"}},
+		{"to": {"yesterday"}},
+		{"from": {"2024-01-08"}, "to": {"2024-01-07"}},
+		{"from": {"2020-01-01"}, "to": {"2024-01-07"}},
+		{"project": {"none"}},
+	} {
+		_, err := statsFilterFromQuery(q, now)
+		assert.Error(t, err, q)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithHistoryRepository(db), WithStatsRepository(db))
+	db.CreateTask(models.Task{Title: "first", Status: models.StatusToDo, Priority: models.High}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "second", Status: models.StatusDone, Priority: models.Medium}, models.TaskCondition{})
+
+	t.Run("Get stats", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/stats", nil)
+		res := httptest.NewRecorder()
+		h.GetStats(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+
+		var s models.Stats
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &s))
+		assert.Equal(t, 2, s.Total)
+		assert.Equal(t, map[models.Status]int{models.StatusToDo: 1, models.StatusDone: 1}, s.ByStatus)
+		assert.Equal(t, 1, s.Completed)
+		assert.Len(t, s.Throughput, 30)
+		assert.Equal(t, 2, s.Throughput[29].Created)
+		assert.Equal(t, 1, s.Burndown[29].Open)
+	})
+
+	t.Run("Get stats of an invalid range", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/stats?from=2024-02-01&to=2024-01-01", nil)
+		res := httptest.NewRecorder()
+		h.GetStats(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *BaseHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	t := h.tasks(r).GetDeletedTasks()
+	resp, err := json.Marshal(t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+func (h *BaseHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	// Get id from URL and parse it to uint64
+	muxID := mux.Vars(r)["id"]
+	id, err := strconv.ParseUint(muxID, 0, 0)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err := h.tasks(r).RestoreTask(id); err != nil {
+		// If no task with the given id is in the trash, respond 404
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	t, err := h.tasks(r).GetTaskByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	h.recordHistory(r, models.HistoryActionRestore, id, nil, nil)
+
+	resp, err := json.Marshal(t)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// PurgeTrash permanently removes the tasks that have been in the trash
+// for longer than retention
+func (h *BaseHandler) PurgeTrash(retention time.Duration) error {
+	purged, err := h.taskRepo.PurgeDeletedTasks(time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	for _, t := range purged {
+		h.removeAttachmentBlobs(&t)
+		h.recordHistory(nil, models.HistoryActionPurge, t.Id, nil, nil)
+	}
+	return nil
+}
+
+// RunTrashPurger purges the trash every interval until ctx is done.
+// A zero or negative interval disables the purger, it returns at once.
+func (h *BaseHandler) RunTrashPurger(ctx context.Context, retention, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.PurgeTrash(retention); err != nil {
+				log.Printf("failed to purge the trash: %s", err.Error())
+			}
+		}
+	}
+}
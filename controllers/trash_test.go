@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrash(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithHistoryRepository(db))
+	db.CreateTask(models.Task{Title: "title 0"}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "title 1"}, models.TaskCondition{})
+
+	do := func(handler http.HandlerFunc, method, url, id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+
+	t.Run("Delete moves the task to the trash", func(t *testing.T) {
+		res := do(h.DeleteTask, "DELETE", "/task/0", "0")
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		res = do(h.GetTaskByID, "GET", "/task/0", "0")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		res = do(h.GetTrash, "GET", "/trash", "")
+		var tasks []models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
+		assert.Len(t, tasks, 1)
+		assert.NotNil(t, tasks[0].DeletedAt)
+	})
+
+	t.Run("Restore task", func(t *testing.T) {
+		res := do(h.RestoreTask, "POST", "/task/0/restore", "0")
+		var task models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &task))
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Nil(t, task.DeletedAt)
+
+		res = do(h.RestoreTask, "POST", "/task/0/restore", "0")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Hard delete", func(t *testing.T) {
+		res := do(h.DeleteTask, "DELETE", "/task/1?hard=true", "1")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, db.GetDeletedTasks())
+		assert.Len(t, db.GetAllTasks(), 1)
+
+		res = do(h.DeleteTask, "DELETE", "/task/1?hard=true", "1")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		history := db.GetTaskHistory(1)
+		assert.Equal(t, models.HistoryActionPurge, history[len(history)-1].Action)
+	})
+
+	t.Run("Purge the trash", func(t *testing.T) {
+		db.DeleteTask(0)
+		assert.NoError(t, h.PurgeTrash(time.Hour))
+		assert.Len(t, db.GetDeletedTasks(), 1)
+
+		assert.NoError(t, h.PurgeTrash(0))
+		assert.Empty(t, db.GetDeletedTasks())
+
+		history := db.GetTaskHistory(0)
+		assert.Equal(t, systemUser, history[len(history)-1].Actor)
+	})
+	t.Run("Purger disabled by a zero interval", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			h.RunTrashPurger(context.Background(), 0, 0)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.Fail(t, "the purger didn't return")
+		}
+	})
+
+	t.Run("Purger purges every interval", func(t *testing.T) {
+		db.CreateTask(models.Task{Title: "title 2"}, models.TaskCondition{})
+		db.DeleteTask(2)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go h.RunTrashPurger(ctx, 0, time.Millisecond)
+		assert.Eventually(t, func() bool { return len(db.GetDeletedTasks()) == 0 }, time.Second, time.Millisecond)
+	})
+}
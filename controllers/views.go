@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"todo-go/models"
+)
+
+// WithViewRepository sets the repository storing the views saved by users
+func WithViewRepository(viewRepo models.ViewRepository) Option {
+	return func(h *BaseHandler) {
+		h.viewRepo = viewRepo
+	}
+}
+
+// userView returns the view identified by the "id" route variable if the user
+// performing the request can see it, and owns it when owned is true.
+// It responds with an error and returns nil otherwise.
+func (h *BaseHandler) userView(w http.ResponseWriter, r *http.Request, owned bool) *models.View {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	if h.viewRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("views are not available"))
+		return nil
+	}
+
+	user := userFromRequest(r)
+	v, err := h.viewRepo.GetViewByID(id)
+	if err != nil || !v.VisibleTo(user) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return nil
+	}
+	if owned && v.Owner != user {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(fmt.Sprintf("view can only be changed by %s", v.Owner)))
+		return nil
+	}
+
+	return v
+}
+
+// decodeView reads the view sent in the request body
+func decodeView(w http.ResponseWriter, r *http.Request) *models.View {
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	var v models.View
+	if err := json.Unmarshal(rBody, &v); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+	if err := v.Validate(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	return &v
+}
+
+// GetViews returns the views of the user performing the request and the views shared by others
+func (h *BaseHandler) GetViews(w http.ResponseWriter, r *http.Request) {
+	if h.viewRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("views are not available"))
+		return
+	}
+
+	resp, err := json.Marshal(h.viewRepo.GetViews(userFromRequest(r)))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// CreateView saves a view owned by the user performing the request
+func (h *BaseHandler) CreateView(w http.ResponseWriter, r *http.Request) {
+	if h.viewRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("views are not available"))
+		return
+	}
+
+	v := decodeView(w, r)
+	if v == nil {
+		return
+	}
+
+	v.Owner = userFromRequest(r)
+	id, err := h.viewRepo.CreateView(*v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	n, err := h.viewRepo.GetViewByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	resp, err := json.Marshal(n)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resp)
+}
+
+func (h *BaseHandler) GetViewByID(w http.ResponseWriter, r *http.Request) {
+	v := h.userView(w, r, false)
+	if v == nil {
+		return
+	}
+
+	resp, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+func (h *BaseHandler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	current := h.userView(w, r, true)
+	if current == nil {
+		return
+	}
+
+	v := decodeView(w, r)
+	if v == nil {
+		return
+	}
+
+	v.Id = current.Id
+	if err := h.viewRepo.UpdateView(*v); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BaseHandler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	v := h.userView(w, r, true)
+	if v == nil {
+		return
+	}
+
+	if err := h.viewRepo.DeleteView(v.Id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+}
+
+// GetViewTasks returns the tasks of a view. Views with a grouping return groups of tasks.
+func (h *BaseHandler) GetViewTasks(w http.ResponseWriter, r *http.Request) {
+	v := h.userView(w, r, false)
+	if v == nil {
+		return
+	}
+
+	f, err := v.TaskFilter()
+	if err != nil {
+		// Views are checked when saved, their filter may only break if the language changes
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	tasks := h.tasks(r).FindTasks(f)
+	var result interface{} = tasks
+	if v.GroupBy != "" {
+		result = models.GroupTasks(tasks, v.GroupBy)
+	}
+
+	resp, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViews(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithViewRepository(db))
+	db.CreateTask(models.Task{Title: "first", Status: models.StatusToDo, Priority: models.High}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "second", Status: models.StatusDone, Priority: models.High}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "third", Status: models.StatusToDo, Priority: models.Low}, models.TaskCondition{})
+
+	do := func(handler http.HandlerFunc, method string, vars map[string]string, user string, body io.Reader) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/", body)
+		req = mux.SetURLVars(req, vars)
+		req.Header.Set("X-User", user)
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+	view := map[string]string{"id": "0"}
+
+	t.Run("Create view", func(t *testing.T) {
+		res := do(h.CreateView, "POST", nil, "john", strings.NewReader(`{"name":"high","filter":"priority>=High","sort":"-title","owner":"jane"}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		var v models.View
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &v))
+		assert.Equal(t, "john", v.Owner)
+		assert.False(t, v.Shared)
+	})
+
+	t.Run("Create invalid views", func(t *testing.T) {
+		res := do(h.CreateView, "POST", nil, "john", strings.NewReader(`{"name":"broken","filter":"priority>="}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Contains(t, res.Body.String(), "invalid filter: position 11")
+
+		res = do(h.CreateView, "POST", nil, "john", strings.NewReader(`{"name":"broken","group_by":"title"}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Get view tasks", func(t *testing.T) {
+		res := do(h.GetViewTasks, "GET", view, "john", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var tasks []models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &tasks))
+		assert.Len(t, tasks, 2)
+		assert.Equal(t, "second", tasks[0].Title)
+	})
+
+	t.Run("Views are private until shared", func(t *testing.T) {
+		res := do(h.GetViewTasks, "GET", view, "jane", nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		res = do(h.GetViews, "GET", nil, "jane", nil)
+		assert.Equal(t, "[]", res.Body.String())
+
+		res = do(h.UpdateView, "PUT", view, "john", strings.NewReader(`{"name":"by status","shared":true,"group_by":"status"}`))
+		assert.Equal(t, http.StatusNoContent, res.Code)
+
+		res = do(h.GetViewByID, "GET", view, "jane", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("Get grouped view tasks", func(t *testing.T) {
+		res := do(h.GetViewTasks, "GET", view, "jane", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var groups []models.TaskGroup
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &groups))
+		assert.Len(t, groups, 2)
+		assert.Equal(t, "TODO", groups[0].Key)
+		assert.Len(t, groups[0].Tasks, 2)
+	})
+
+	t.Run("Only the owner changes a view", func(t *testing.T) {
+		res := do(h.UpdateView, "PUT", view, "jane", strings.NewReader(`{"name":"mine"}`))
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		res = do(h.DeleteView, "DELETE", view, "jane", nil)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+
+		res = do(h.DeleteView, "DELETE", view, "john", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		res = do(h.GetViewByID, "GET", view, "john", nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("Views without repository", func(t *testing.T) {
+		h := NewBaseHandler(db)
+		view := map[string]string{"id": "0"}
+		res := do(h.GetViews, "GET", nil, "john", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.CreateView, "POST", nil, "john", strings.NewReader(`{"name":"mine","filter":"status:TODO"}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.GetViewByID, "GET", view, "john", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.UpdateView, "PUT", view, "john", strings.NewReader(`{"name":"mine","filter":"status:TODO"}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.DeleteView, "DELETE", view, "john", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.GetViewTasks, "GET", view, "john", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+	})
+}
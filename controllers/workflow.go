@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GetWorkflow returns the statuses and the allowed transitions
+// so clients can render the valid actions of a task
+func (h *BaseHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	resp, err := json.Marshal(h.workflow)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
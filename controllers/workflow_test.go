@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWorkflow(t *testing.T) {
+	t.Run("Get the workflow", func(t *testing.T) {
+		h := NewBaseHandler(&m)
+		req, _ := http.NewRequest("GET", "/workflow", nil)
+		res := httptest.NewRecorder()
+
+		h.GetWorkflow(res, req)
+
+		var wf models.Workflow
+		err := json.Unmarshal(res.Body.Bytes(), &wf)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "application/json", res.Result().Header["Content-Type"][0])
+		assert.Equal(t, models.DefaultWorkflow(), &wf)
+	})
+}
+
+func TestWorkflowEnforcement(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db)
+	id, _ := db.CreateTask(models.Task{Title: "title", Status: models.StatusToDo}, models.TaskCondition{})
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("PUT", "/task/0", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": "0"})
+		res := httptest.NewRecorder()
+		h.UpdateTask(res, req)
+		return res
+	}
+
+	t.Run("Create task without status", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/task", strings.NewReader(`{"title":"new title"}`))
+		res := httptest.NewRecorder()
+
+		h.CreateTask(res, req)
+
+		var task models.Task
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &task))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Equal(t, models.Status(models.StatusToDo), task.Status)
+	})
+
+	t.Run("Create task with unknown status", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/task", strings.NewReader(`{"title":"new title","status":"UNKNOWN"}`))
+		res := httptest.NewRecorder()
+
+		h.CreateTask(res, req)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Illegal transition", func(t *testing.T) {
+		res := update(`{"title":"title","status":"DONE"}`)
+		assert.Equal(t, http.StatusConflict, res.Code)
+
+		task, _ := db.GetTaskByID(id)
+		assert.Equal(t, models.Status(models.StatusToDo), task.Status)
+	})
+
+	t.Run("Unknown status", func(t *testing.T) {
+		res := update(`{"title":"title","status":"UNKNOWN"}`)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Legal transitions", func(t *testing.T) {
+		res := update(`{"title":"title","status":"INPROGRESS"}`)
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		res = update(`{"title":"title","status":"DONE"}`)
+		assert.Equal(t, http.StatusNoContent, res.Code)
+
+		task, _ := db.GetTaskByID(id)
+		assert.Equal(t, models.Status(models.StatusDone), task.Status)
+	})
+
+	t.Run("Update without status keeps the current one", func(t *testing.T) {
+		res := update(`{"title":"other title"}`)
+		assert.Equal(t, http.StatusNoContent, res.Code)
+
+		task, _ := db.GetTaskByID(id)
+		assert.Equal(t, models.Status(models.StatusDone), task.Status)
+		assert.Equal(t, "other title", task.Title)
+	})
+
+	t.Run("Update non existing task", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "/task/99", strings.NewReader(`{"title":"title"}`))
+		req = mux.SetURLVars(req, map[string]string{"id": "99"})
+		res := httptest.NewRecorder()
+		h.UpdateTask(res, req)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
@@ -0,0 +1,372 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+	"todo-go/models"
+)
+
+// WithWorklogRepository sets the repository storing the time logged on tasks
+func WithWorklogRepository(worklogRepo models.WorklogRepository) Option {
+	return func(h *BaseHandler) {
+		h.worklogRepo = worklogRepo
+	}
+}
+
+// taskWorklog returns the worklog identified by the "worklogId" route variable
+// if it belongs to the task identified by the "id" route variable and
+// was logged by the user performing the request.
+// It responds with an error and returns nil otherwise.
+func (h *BaseHandler) taskWorklog(w http.ResponseWriter, r *http.Request) *models.Worklog {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+	worklogID, err := uintVar(r, "worklogId")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	if h.worklogRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("worklogs are not available"))
+		return nil
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return nil
+	}
+	wl, err := h.worklogRepo.GetWorklogByID(worklogID)
+	if err != nil || wl.TaskId != id {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return nil
+	}
+
+	// Only the user who logged the time can change it
+	if wl.User != userFromRequest(r) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(fmt.Sprintf("worklog can only be changed by %s", wl.User)))
+		return nil
+	}
+
+	return wl
+}
+
+// decodeWorklog reads the worklog sent in the request body.
+// Worklogs without start date started the given minutes ago.
+func decodeWorklog(w http.ResponseWriter, r *http.Request) *models.Worklog {
+	rBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+
+	var wl models.Worklog
+	if err := json.Unmarshal(rBody, &wl); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return nil
+	}
+	if wl.Minutes <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("worklog minutes must be positive"))
+		return nil
+	}
+
+	d := time.Duration(wl.Minutes) * time.Minute
+	if wl.StartedAt.IsZero() {
+		wl.StartedAt = time.Now().Add(-d)
+	}
+	end := wl.StartedAt.Add(d)
+	wl.EndedAt = &end
+
+	return &wl
+}
+
+// writeWorklog responds with the worklog identified by id
+func (h *BaseHandler) writeWorklog(w http.ResponseWriter, id uint64, code int) {
+	n, err := h.worklogRepo.GetWorklogByID(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	resp, err := json.Marshal(n)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(resp)
+}
+
+func (h *BaseHandler) GetTaskWorklogs(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.worklogRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("worklogs are not available"))
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	resp, err := json.Marshal(h.worklogRepo.GetTaskWorklogs(id))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// CreateWorklog logs time spent on a task by the user performing the request
+func (h *BaseHandler) CreateWorklog(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.worklogRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("worklogs are not available"))
+		return
+	}
+
+	wl := decodeWorklog(w, r)
+	if wl == nil {
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	wl.TaskId = id
+	wl.User = userFromRequest(r)
+	worklogID, err := h.worklogRepo.CreateWorklog(*wl)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	h.writeWorklog(w, worklogID, http.StatusCreated)
+}
+
+func (h *BaseHandler) UpdateWorklog(w http.ResponseWriter, r *http.Request) {
+	current := h.taskWorklog(w, r)
+	if current == nil {
+		return
+	}
+
+	if current.Running() {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("timer is running, stop it first"))
+		return
+	}
+
+	wl := decodeWorklog(w, r)
+	if wl == nil {
+		return
+	}
+
+	wl.Id = current.Id
+	if err := h.worklogRepo.UpdateWorklog(*wl); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteWorklog removes a worklog. Deleting the worklog of a running timer cancels it.
+func (h *BaseHandler) DeleteWorklog(w http.ResponseWriter, r *http.Request) {
+	wl := h.taskWorklog(w, r)
+	if wl == nil {
+		return
+	}
+
+	if err := h.worklogRepo.DeleteWorklog(wl.Id); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+}
+
+// StartTimer starts logging time on a task for the user performing the request.
+// A user can only run one timer at a time.
+func (h *BaseHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.worklogRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("worklogs are not available"))
+		return
+	}
+
+	if _, err := h.tasks(r).GetTaskByID(id); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+		return
+	}
+
+	// The repository checks the user doesn't run another timer along with the creation
+	worklogID, err := h.worklogRepo.CreateWorklog(models.Worklog{TaskId: id, User: userFromRequest(r), StartedAt: time.Now()})
+	if errors.Is(err, models.ErrTimerRunning) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	h.writeWorklog(w, worklogID, http.StatusCreated)
+}
+
+// StopTimer stops the timer the user performing the request runs on a task.
+// The time spent is rounded to the nearest minute.
+func (h *BaseHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := uintVar(r, "id")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if h.worklogRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("worklogs are not available"))
+		return
+	}
+
+	wl, err := h.worklogRepo.GetRunningWorklog(userFromRequest(r))
+	if err != nil || wl.TaskId != id {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no timer running on this task"))
+		return
+	}
+
+	end := time.Now()
+	wl.EndedAt = &end
+	wl.Minutes = int(math.Round(end.Sub(wl.StartedAt).Minutes()))
+	if err := h.worklogRepo.UpdateWorklog(*wl); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	h.writeWorklog(w, wl.Id, http.StatusOK)
+}
+
+// worklogFilterFromQuery builds a WorklogFilter from the query string parameters:
+//   - from: first day of the range, formatted as 2006-01-02
+//   - to: last day of the range, included, formatted as 2006-01-02
+//   - user: user who logged the time
+func worklogFilterFromQuery(q url.Values) (models.WorklogFilter, error) {
+	f := models.WorklogFilter{User: q.Get("user")}
+
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid from %s: must be formatted as %s", v, time.DateOnly)
+		}
+		f.From = from
+	}
+
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid to %s: must be formatted as %s", v, time.DateOnly)
+		}
+		f.To = to.AddDate(0, 0, 1)
+	}
+
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return f, fmt.Errorf("from must not be after to")
+	}
+
+	return f, nil
+}
+
+// GetTimeReport returns the time logged versus the time estimated over a date range,
+// grouped by task, project or user with the group_by query string parameter.
+func (h *BaseHandler) GetTimeReport(w http.ResponseWriter, r *http.Request) {
+	if h.worklogRepo == nil {
+		w.WriteHeader(http.StatusNotImplemented)
+		w.Write([]byte("worklogs are not available"))
+		return
+	}
+
+	f, err := worklogFilterFromQuery(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	groupBy := models.TimeReportGroup(r.URL.Query().Get("group_by"))
+	if groupBy == "" {
+		groupBy = models.TimeReportByTask
+	}
+
+	// Time logged on tasks in the trash is still reported
+	tasks := append(h.tasks(r).GetAllTasks(), h.tasks(r).GetDeletedTasks()...)
+	report, err := models.NewTimeReport(groupBy, tasks, h.worklogRepo.FindWorklogs(f))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	resp, err := json.Marshal(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorklogs(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithWorklogRepository(db))
+	db.CreateTask(models.Task{Title: "title", EstimateMinutes: 120}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "other"}, models.TaskCondition{})
+
+	do := func(handler http.HandlerFunc, method, target string, vars map[string]string, user string, body io.Reader) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, target, body)
+		req = mux.SetURLVars(req, vars)
+		req.Header.Set("X-User", user)
+		res := httptest.NewRecorder()
+		handler(res, req)
+		return res
+	}
+	task := map[string]string{"id": "0"}
+
+	t.Run("Start and stop timer", func(t *testing.T) {
+		res := do(h.StartTimer, "POST", "/", task, "john", nil)
+		assert.Equal(t, http.StatusCreated, res.Code)
+		var wl models.Worklog
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &wl))
+		assert.True(t, wl.Running())
+		assert.Equal(t, "john", wl.User)
+
+		res = do(h.StartTimer, "POST", "/", map[string]string{"id": "1"}, "john", nil)
+		assert.Equal(t, http.StatusConflict, res.Code)
+
+		res = do(h.StopTimer, "POST", "/", map[string]string{"id": "1"}, "john", nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		res = do(h.StopTimer, "POST", "/", task, "jane", nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		res = do(h.StopTimer, "POST", "/", task, "john", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &wl))
+		assert.False(t, wl.Running())
+		assert.Equal(t, 0, wl.Minutes)
+	})
+
+	t.Run("Log time", func(t *testing.T) {
+		res := do(h.CreateWorklog, "POST", "/", task, "jane", strings.NewReader(`{"started_at":"2026-03-02T09:00:00Z","minutes":90,"note":"review"}`))
+		assert.Equal(t, http.StatusCreated, res.Code)
+		var wl models.Worklog
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &wl))
+		assert.Equal(t, "jane", wl.User)
+		assert.Equal(t, "2026-03-02T10:30:00Z", wl.EndedAt.Format("2006-01-02T15:04:05Z07:00"))
+
+		res = do(h.CreateWorklog, "POST", "/", task, "jane", strings.NewReader(`{"minutes":0}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = do(h.CreateWorklog, "POST", "/", map[string]string{"id": "99"}, "jane", strings.NewReader(`{"minutes":10}`))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		res = do(h.GetTaskWorklogs, "GET", "/", task, "", nil)
+		var worklogs []models.Worklog
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &worklogs))
+		assert.Len(t, worklogs, 2)
+	})
+
+	t.Run("Edit worklog", func(t *testing.T) {
+		worklog := map[string]string{"id": "0", "worklogId": "1"}
+		res := do(h.UpdateWorklog, "PUT", "/", worklog, "john", strings.NewReader(`{"minutes":30}`))
+		assert.Equal(t, http.StatusForbidden, res.Code)
+
+		res = do(h.UpdateWorklog, "PUT", "/", worklog, "jane", strings.NewReader(`{"started_at":"2026-03-02T09:00:00Z","minutes":30}`))
+		assert.Equal(t, http.StatusNoContent, res.Code)
+		wl, _ := db.GetWorklogByID(1)
+		assert.Equal(t, 30, wl.Minutes)
+		assert.Empty(t, wl.Note)
+	})
+
+	t.Run("Time report", func(t *testing.T) {
+		res := do(h.GetTimeReport, "GET", "/reports/time?from=2026-03-01&to=2026-03-02", nil, "", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var r models.TimeReport
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.Equal(t, models.TimeReportByTask, r.GroupBy)
+		assert.Len(t, r.Rows, 1)
+		assert.Equal(t, 30, r.Rows[0].LoggedMinutes)
+		assert.Equal(t, 120, r.Rows[0].EstimateMinutes)
+
+		res = do(h.GetTimeReport, "GET", "/reports/time?group_by=user", nil, "", nil)
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &r))
+		assert.Len(t, r.Rows, 2)
+
+		res = do(h.GetTimeReport, "GET", "/reports/time?from=2026-03-03&to=2026-03-01", nil, "", nil)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		res = do(h.GetTimeReport, "GET", "/reports/time?group_by=status", nil, "", nil)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Delete worklog", func(t *testing.T) {
+		res := do(h.DeleteWorklog, "DELETE", "/", map[string]string{"id": "1", "worklogId": "1"}, "jane", nil)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+
+		res = do(h.DeleteWorklog, "DELETE", "/", map[string]string{"id": "0", "worklogId": "1"}, "jane", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Len(t, db.GetTaskWorklogs(0), 1)
+	})
+
+	t.Run("Reject negative estimates", func(t *testing.T) {
+		res := do(h.CreateTask, "POST", "/", nil, "", strings.NewReader(`{"title":"t","estimate_minutes":-5}`))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("Worklogs without repository", func(t *testing.T) {
+		h := NewBaseHandler(db)
+		worklog := map[string]string{"id": "0", "worklogId": "0"}
+		res := do(h.GetTaskWorklogs, "GET", "/", task, "", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.CreateWorklog, "POST", "/", task, "jane", strings.NewReader(`{"minutes":10}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.UpdateWorklog, "PUT", "/", worklog, "jane", strings.NewReader(`{"minutes":10}`))
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.DeleteWorklog, "DELETE", "/", worklog, "jane", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.StartTimer, "POST", "/", task, "john", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.StopTimer, "POST", "/", task, "john", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+		res = do(h.GetTimeReport, "GET", "/reports/time", nil, "", nil)
+		assert.Equal(t, http.StatusNotImplemented, res.Code)
+	})
+}
+
+func TestStartTimerConcurrently(t *testing.T) {
+	db := databases.NewInMemoryDatabase()
+	h := NewBaseHandler(db, WithWorklogRepository(db))
+	db.CreateTask(models.Task{Title: "title"}, models.TaskCondition{})
+
+	codes := make(chan int, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(codes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("POST", "/task/0/timer/start", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "0"})
+			req.Header.Set("X-User", "john")
+			res := httptest.NewRecorder()
+			h.StartTimer(res, req)
+			codes <- res.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	// Only one of the concurrent starts runs a timer, the others conflict
+	count := make(map[int]int)
+	for code := range codes {
+		count[code]++
+	}
+	assert.Equal(t, map[int]int{http.StatusCreated: 1, http.StatusConflict: cap(codes) - 1}, count)
+}
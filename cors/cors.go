@@ -0,0 +1,63 @@
+// Package cors lets browser frontends hosted on other origins call the API
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/handlers"
+)
+
+// Options configures the cross-origin requests allowed by the API
+type Options struct {
+	// Origins allowed to call the API, eg: "https://app.example.com", or "*" for any
+	AllowedOrigins []string
+	AllowedMethods []string
+	// Request headers frontends may send, on top of the CORS-safelisted ones
+	AllowedHeaders []string
+	// Response headers frontends may read, on top of the CORS-safelisted ones
+	ExposedHeaders []string
+	// Lets frontends send cookies and credentials, only with explicit origins
+	AllowCredentials bool
+	// How long browsers cache the result of preflight requests, up to 10 minutes
+	MaxAge time.Duration
+}
+
+// Split splits a comma separated list of values, as found in the configuration
+func Split(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Handler returns a middleware answering the preflight requests and adding the
+// CORS headers to the responses of allowed origins. It must wrap the whole router
+// so that preflight OPTIONS requests are answered for every route.
+func Handler(opts Options) (func(http.Handler) http.Handler, error) {
+	for _, o := range opts.AllowedOrigins {
+		if o == "*" && opts.AllowCredentials {
+			return nil, fmt.Errorf("credentials can't be allowed for any origin, origins must be listed")
+		}
+	}
+	if opts.MaxAge < 0 || opts.MaxAge > 10*time.Minute {
+		return nil, fmt.Errorf("max age must be between 0 and 10m")
+	}
+
+	corsOpts := []handlers.CORSOption{
+		handlers.AllowedOrigins(opts.AllowedOrigins),
+		handlers.AllowedMethods(opts.AllowedMethods),
+		handlers.AllowedHeaders(opts.AllowedHeaders),
+		handlers.ExposedHeaders(opts.ExposedHeaders),
+		handlers.MaxAge(int(opts.MaxAge.Seconds())),
+	}
+	if opts.AllowCredentials {
+		corsOpts = append(corsOpts, handlers.AllowCredentials())
+	}
+	return handlers.CORS(corsOpts...), nil
+}
@@ -0,0 +1,83 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, []string{"GET", "POST"}, Split(" GET, ,POST,"))
+	assert.Nil(t, Split(""))
+}
+
+func TestHandler(t *testing.T) {
+	r := mux.NewRouter()
+	r.HandleFunc("/task/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "abc")
+	}).Methods("GET", "PUT")
+
+	cors, err := Handler(Options{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "PUT"},
+		AllowedHeaders:   []string{"Content-Type", "X-User"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           5 * time.Minute,
+	})
+	assert.NoError(t, err)
+	h := cors(r)
+
+	do := func(method, origin string, header http.Header) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/task/1", nil)
+		req.Header.Set("Origin", origin)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		return res
+	}
+
+	t.Run("Answer preflight requests", func(t *testing.T) {
+		res := do("OPTIONS", "https://app.example.com", http.Header{
+			"Access-Control-Request-Method":  {"PUT"},
+			"Access-Control-Request-Headers": {"Content-Type, X-User"},
+		})
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "https://app.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "PUT", res.Header().Get("Access-Control-Allow-Methods"))
+		assert.Contains(t, res.Header().Get("Access-Control-Allow-Headers"), "X-User")
+		assert.Equal(t, "true", res.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "300", res.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("Reject preflight requests of disallowed methods", func(t *testing.T) {
+		res := do("OPTIONS", "https://app.example.com", http.Header{"Access-Control-Request-Method": {"DELETE"}})
+		assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("Add headers to requests of allowed origins", func(t *testing.T) {
+		res := do("GET", "https://app.example.com", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "https://app.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "X-Request-Id", res.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("Ignore other origins", func(t *testing.T) {
+		res := do("GET", "https://evil.example.com", nil)
+		assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestHandlerInvalidOptions(t *testing.T) {
+	_, err := Handler(Options{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	assert.Error(t, err)
+
+	_, err = Handler(Options{AllowedOrigins: []string{"*"}, MaxAge: time.Hour})
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package databases
+
+import (
+	"fmt"
+	"time"
+	"todo-go/models"
+)
+
+func (db *InMemoryDatabase) AddAttachment(taskId uint64, a models.Attachment) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.indexOf(taskId)
+	if !ok || db.Tasks[k].DeletedAt != nil {
+		return 0, fmt.Errorf("error adding attachment: no task with id %v exists", taskId)
+	}
+
+	a.Id = db.nextAttachmentID
+	a.CreatedAt = time.Now()
+	db.nextAttachmentID++
+	db.Tasks[k].Attachments = append(db.Tasks[k].Attachments, a)
+
+	return a.Id, nil
+}
+
+func (db *InMemoryDatabase) DeleteAttachment(taskId uint64, attachmentId uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.indexOf(taskId)
+	if !ok || db.Tasks[k].DeletedAt != nil {
+		return fmt.Errorf("error deleting attachment: no task with id %v exists", taskId)
+	}
+
+	attachments := db.Tasks[k].Attachments
+	for i := range attachments {
+		if attachments[i].Id == attachmentId {
+			// Don't modify the backing array in place, it may be shared with
+			// copies of the task handed out before
+			n := make([]models.Attachment, 0, len(attachments)-1)
+			n = append(n, attachments[:i]...)
+			db.Tasks[k].Attachments = append(n, attachments[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error deleting attachment id %d: no attachment with id %v exists on task id %d", attachmentId, attachmentId, taskId)
+}
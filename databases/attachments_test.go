@@ -0,0 +1,51 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachments(t *testing.T) {
+	db := NewInMemoryDatabase()
+	taskID, _ := db.CreateTask(models.Task{Title: "Test Title 0"}, models.TaskCondition{})
+
+	t.Run("Add attachments", func(t *testing.T) {
+		id, err := db.AddAttachment(taskID, models.Attachment{Filename: "a.png", Key: "tasks/0/a"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), id)
+		id, err = db.AddAttachment(taskID, models.Attachment{Filename: "b.log", Key: "tasks/0/b"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+
+		task, _ := db.GetTaskByID(taskID)
+		assert.Len(t, task.Attachments, 2)
+		assert.False(t, task.Attachments[0].CreatedAt.IsZero())
+	})
+
+	t.Run("Add attachment to non existing task", func(t *testing.T) {
+		_, err := db.AddAttachment(99, models.Attachment{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Attachments are not set on creation or update", func(t *testing.T) {
+		id, _ := db.CreateTask(models.Task{Attachments: []models.Attachment{{Filename: "a.png"}}}, models.TaskCondition{})
+		task, _ := db.GetTaskByID(id)
+		assert.Empty(t, task.Attachments)
+
+		db.UpdateTask(models.Task{Id: taskID}, models.TaskCondition{})
+		task, _ = db.GetTaskByID(taskID)
+		assert.Len(t, task.Attachments, 2)
+	})
+
+	t.Run("Delete attachment", func(t *testing.T) {
+		assert.NoError(t, db.DeleteAttachment(taskID, 0))
+		task, _ := db.GetTaskByID(taskID)
+		assert.Len(t, task.Attachments, 1)
+		assert.Equal(t, "b.log", task.Attachments[0].Filename)
+
+		assert.Error(t, db.DeleteAttachment(taskID, 0))
+		assert.Error(t, db.DeleteAttachment(99, 1))
+	})
+}
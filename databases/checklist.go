@@ -0,0 +1,120 @@
+package databases
+
+import (
+	"fmt"
+	"todo-go/models"
+)
+
+// setChecklist replaces the checklist of the task at index k and updates its completion.
+// The checklist must not share its backing array with the current one as it may be
+// shared with copies of the task handed out before.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) setChecklist(k int, items []models.ChecklistItem) {
+	if len(items) == 0 {
+		items = nil
+	}
+	db.Tasks[k].Checklist = items
+	db.Tasks[k].ChecklistCompletion = models.ChecklistCompletion(items)
+}
+
+// checklistItemIndex returns the index of the item in the checklist of the task at index k.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) checklistItemIndex(k int, itemId uint64) (int, bool) {
+	for i, item := range db.Tasks[k].Checklist {
+		if item.Id == itemId {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// AddChecklistItem appends an item to the checklist of a task
+func (db *InMemoryDatabase) AddChecklistItem(taskId uint64, i models.ChecklistItem) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.liveTaskIndex(taskId)
+	if !ok {
+		return 0, fmt.Errorf("error adding checklist item: no task with id %v exists", taskId)
+	}
+
+	i.Id = db.nextChecklistID
+	db.nextChecklistID++
+	items := append(append(make([]models.ChecklistItem, 0, len(db.Tasks[k].Checklist)+1), db.Tasks[k].Checklist...), i)
+	db.setChecklist(k, items)
+
+	return i.Id, nil
+}
+
+// ToggleChecklistItem checks an unchecked item and unchecks a checked one
+func (db *InMemoryDatabase) ToggleChecklistItem(taskId uint64, itemId uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.liveTaskIndex(taskId)
+	if !ok {
+		return fmt.Errorf("error toggling checklist item: no task with id %v exists", taskId)
+	}
+	i, ok := db.checklistItemIndex(k, itemId)
+	if !ok {
+		return fmt.Errorf("error toggling checklist item id %d: no item with id %v exists on task id %d", itemId, itemId, taskId)
+	}
+
+	items := append(make([]models.ChecklistItem, 0, len(db.Tasks[k].Checklist)), db.Tasks[k].Checklist...)
+	items[i].Checked = !items[i].Checked
+	db.setChecklist(k, items)
+
+	return nil
+}
+
+// MoveChecklistItem moves an item to the given index of the checklist.
+// Indexes past the end of the checklist move the item last.
+func (db *InMemoryDatabase) MoveChecklistItem(taskId uint64, itemId uint64, index int) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.liveTaskIndex(taskId)
+	if !ok {
+		return fmt.Errorf("error moving checklist item: no task with id %v exists", taskId)
+	}
+	i, ok := db.checklistItemIndex(k, itemId)
+	if !ok {
+		return fmt.Errorf("error moving checklist item id %d: no item with id %v exists on task id %d", itemId, itemId, taskId)
+	}
+	if index < 0 {
+		return fmt.Errorf("error moving checklist item id %d: index must not be negative", itemId)
+	}
+
+	checklist := db.Tasks[k].Checklist
+	items := make([]models.ChecklistItem, 0, len(checklist))
+	items = append(items, checklist[:i]...)
+	items = append(items, checklist[i+1:]...)
+	if index > len(items) {
+		index = len(items)
+	}
+	items = append(items[:index], append([]models.ChecklistItem{checklist[i]}, items[index:]...)...)
+	db.setChecklist(k, items)
+
+	return nil
+}
+
+func (db *InMemoryDatabase) DeleteChecklistItem(taskId uint64, itemId uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.liveTaskIndex(taskId)
+	if !ok {
+		return fmt.Errorf("error deleting checklist item: no task with id %v exists", taskId)
+	}
+	i, ok := db.checklistItemIndex(k, itemId)
+	if !ok {
+		return fmt.Errorf("error deleting checklist item id %d: no item with id %v exists on task id %d", itemId, itemId, taskId)
+	}
+
+	checklist := db.Tasks[k].Checklist
+	items := make([]models.ChecklistItem, 0, len(checklist)-1)
+	items = append(items, checklist[:i]...)
+	db.setChecklist(k, append(items, checklist[i+1:]...))
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func checklistOrder(db *InMemoryDatabase, taskId uint64) []uint64 {
+	t, _ := db.GetTaskByID(taskId)
+	ids := make([]uint64, 0, len(t.Checklist))
+	for _, i := range t.Checklist {
+		ids = append(ids, i.Id)
+	}
+	return ids
+}
+
+func TestChecklist(t *testing.T) {
+	db := NewInMemoryDatabase()
+	taskID, _ := db.CreateTask(models.Task{Title: "Test Title 0"}, models.TaskCondition{})
+
+	t.Run("Add items", func(t *testing.T) {
+		for _, text := range []string{"first", "second", "third"} {
+			_, err := db.AddChecklistItem(taskID, models.ChecklistItem{Text: text})
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []uint64{0, 1, 2}, checklistOrder(db, taskID))
+
+		task, _ := db.GetTaskByID(taskID)
+		assert.Equal(t, 0, *task.ChecklistCompletion)
+
+		_, err := db.AddChecklistItem(99, models.ChecklistItem{Text: "text"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Toggle item", func(t *testing.T) {
+		before, _ := db.GetTaskByID(taskID)
+		old := *before
+
+		assert.NoError(t, db.ToggleChecklistItem(taskID, 1))
+		task, _ := db.GetTaskByID(taskID)
+		assert.True(t, task.Checklist[1].Checked)
+		assert.Equal(t, 33, *task.ChecklistCompletion)
+		// Copies handed out before are left untouched
+		assert.False(t, old.Checklist[1].Checked)
+
+		assert.Error(t, db.ToggleChecklistItem(taskID, 99))
+	})
+
+	t.Run("Move item", func(t *testing.T) {
+		assert.NoError(t, db.MoveChecklistItem(taskID, 2, 0))
+		assert.Equal(t, []uint64{2, 0, 1}, checklistOrder(db, taskID))
+
+		assert.NoError(t, db.MoveChecklistItem(taskID, 2, 99))
+		assert.Equal(t, []uint64{0, 1, 2}, checklistOrder(db, taskID))
+
+		assert.Error(t, db.MoveChecklistItem(taskID, 2, -1))
+		assert.Error(t, db.MoveChecklistItem(taskID, 99, 0))
+	})
+
+	t.Run("Delete item", func(t *testing.T) {
+		assert.NoError(t, db.DeleteChecklistItem(taskID, 0))
+		assert.Equal(t, []uint64{1, 2}, checklistOrder(db, taskID))
+		task, _ := db.GetTaskByID(taskID)
+		assert.Equal(t, 50, *task.ChecklistCompletion)
+
+		assert.NoError(t, db.DeleteChecklistItem(taskID, 1))
+		assert.NoError(t, db.DeleteChecklistItem(taskID, 2))
+		task, _ = db.GetTaskByID(taskID)
+		assert.Nil(t, task.Checklist)
+		assert.Nil(t, task.ChecklistCompletion)
+
+		assert.Error(t, db.DeleteChecklistItem(taskID, 2))
+	})
+}
@@ -0,0 +1,94 @@
+package databases
+
+import (
+	"fmt"
+	"time"
+	"todo-go/models"
+)
+
+func (db *InMemoryDatabase) GetTaskComments(taskId uint64) []models.Comment {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	comments := make([]models.Comment, 0)
+	for _, c := range db.Comments {
+		if c.TaskId == taskId {
+			comments = append(comments, c)
+		}
+	}
+
+	return comments
+}
+
+func (db *InMemoryDatabase) GetCommentByID(id uint64) (*models.Comment, error) {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	for k, c := range db.Comments {
+		if c.Id == id {
+			comment := db.Comments[k]
+			return &comment, nil
+		}
+	}
+
+	return &models.Comment{}, fmt.Errorf("no comment with id %v exists", id)
+}
+
+func (db *InMemoryDatabase) CreateComment(c models.Comment) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	if k, ok := db.indexOf(c.TaskId); !ok || db.Tasks[k].DeletedAt != nil {
+		return 0, fmt.Errorf("error creating comment: no task with id %v exists", c.TaskId)
+	}
+
+	d := time.Now()
+	c.Id = db.nextCommentID
+	c.CreatedAt = d
+	c.UpdatedAt = d
+	db.nextCommentID++
+	db.Comments = append(db.Comments, c)
+
+	return c.Id, nil
+}
+
+func (db *InMemoryDatabase) UpdateComment(c models.Comment) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	for k := range db.Comments {
+		if db.Comments[k].Id == c.Id {
+			db.Comments[k].Body = c.Body
+			db.Comments[k].UpdatedAt = time.Now()
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error updating comment id %d: no comment with id %v exists", c.Id, c.Id)
+}
+
+func (db *InMemoryDatabase) DeleteComment(id uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	for k := range db.Comments {
+		if db.Comments[k].Id == id {
+			db.Comments = append(db.Comments[:k], db.Comments[k+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error deleting comment id %d: no comment with id %v exists", id, id)
+}
+
+// removeTaskComments removes the comments of a task.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) removeTaskComments(taskId uint64) {
+	comments := db.Comments[:0]
+	for _, c := range db.Comments {
+		if c.TaskId != taskId {
+			comments = append(comments, c)
+		}
+	}
+	db.Comments = comments
+}
@@ -0,0 +1,63 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComments(t *testing.T) {
+	db := NewInMemoryDatabase()
+	taskID, _ := db.CreateTask(models.Task{Title: "Test Title 0"}, models.TaskCondition{})
+	otherID, _ := db.CreateTask(models.Task{Title: "Test Title 1"}, models.TaskCondition{})
+
+	t.Run("Create comments", func(t *testing.T) {
+		id, err := db.CreateComment(models.Comment{TaskId: taskID, Author: "john", Body: "first"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), id)
+
+		id, err = db.CreateComment(models.Comment{TaskId: otherID, Author: "john", Body: "second"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+
+		c, err := db.GetCommentByID(0)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", c.Body)
+		assert.False(t, c.CreatedAt.IsZero())
+	})
+
+	t.Run("Create comment on non existing task", func(t *testing.T) {
+		_, err := db.CreateComment(models.Comment{TaskId: 99, Body: "body"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Get task comments", func(t *testing.T) {
+		comments := db.GetTaskComments(taskID)
+		assert.Len(t, comments, 1)
+		assert.Equal(t, "first", comments[0].Body)
+	})
+
+	t.Run("Update comment", func(t *testing.T) {
+		assert.NoError(t, db.UpdateComment(models.Comment{Id: 0, Author: "jane", Body: "edited"}))
+		c, _ := db.GetCommentByID(0)
+		assert.Equal(t, "edited", c.Body)
+		assert.Equal(t, "john", c.Author)
+		assert.Error(t, db.UpdateComment(models.Comment{Id: 99}))
+	})
+
+	t.Run("Comments are retained in the trash and removed on purge", func(t *testing.T) {
+		db.DeleteTask(taskID)
+		assert.Len(t, db.GetTaskComments(taskID), 1)
+
+		db.PurgeTask(taskID)
+		assert.Empty(t, db.GetTaskComments(taskID))
+		assert.Len(t, db.GetTaskComments(otherID), 1)
+	})
+
+	t.Run("Delete comment", func(t *testing.T) {
+		assert.NoError(t, db.DeleteComment(1))
+		assert.Empty(t, db.GetTaskComments(otherID))
+		assert.Error(t, db.DeleteComment(1))
+	})
+}
@@ -0,0 +1,29 @@
+package databases
+
+import (
+	"todo-go/models"
+)
+
+func (db *InMemoryDatabase) AddHistoryEntry(e models.HistoryEntry) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	e.Id = uint64(len(db.History))
+	db.History = append(db.History, e)
+
+	return e.Id, nil
+}
+
+func (db *InMemoryDatabase) GetTaskHistory(taskId uint64) []models.HistoryEntry {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	entries := make([]models.HistoryEntry, 0)
+	for _, e := range db.History {
+		if e.TaskId == taskId {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
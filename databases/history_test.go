@@ -0,0 +1,46 @@
+package databases
+
+import (
+	"testing"
+	"time"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddHistoryEntry(t *testing.T) {
+	db := NewInMemoryDatabase()
+
+	t.Run("Add entries", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			id, err := db.AddHistoryEntry(models.HistoryEntry{
+				TaskId:    uint64(i % 2),
+				Action:    models.HistoryActionUpdate,
+				Actor:     "john",
+				Timestamp: time.Now(),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(i), id)
+		}
+	})
+}
+
+func TestGetTaskHistory(t *testing.T) {
+	db := NewInMemoryDatabase()
+	db.AddHistoryEntry(models.HistoryEntry{TaskId: 0, Action: models.HistoryActionCreate})
+	db.AddHistoryEntry(models.HistoryEntry{TaskId: 1, Action: models.HistoryActionCreate})
+	db.AddHistoryEntry(models.HistoryEntry{TaskId: 0, Action: models.HistoryActionUpdate})
+
+	t.Run("Get history of a task", func(t *testing.T) {
+		entries := db.GetTaskHistory(0)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, models.HistoryActionCreate, entries[0].Action)
+		assert.Equal(t, models.HistoryActionUpdate, entries[1].Action)
+	})
+
+	t.Run("Get history of a task without entries", func(t *testing.T) {
+		entries := db.GetTaskHistory(99)
+		assert.NotNil(t, entries)
+		assert.Empty(t, entries)
+	})
+}
@@ -0,0 +1,95 @@
+package databases
+
+import (
+	"fmt"
+	"time"
+	"todo-go/models"
+)
+
+// GetTaskLinks returns the links of a task, from either side.
+// Links to tasks in the trash are hidden until they are restored.
+func (db *InMemoryDatabase) GetTaskLinks(taskId uint64) []models.Link {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	links := make([]models.Link, 0)
+	for _, l := range db.Links {
+		if l.SourceId != taskId && l.TargetId != taskId {
+			continue
+		}
+		if _, ok := db.liveTaskIndex(l.SourceId); !ok {
+			continue
+		}
+		if _, ok := db.liveTaskIndex(l.TargetId); !ok {
+			continue
+		}
+		links = append(links, l)
+	}
+
+	return links
+}
+
+func (db *InMemoryDatabase) GetLinkByID(id uint64) (*models.Link, error) {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	for k, l := range db.Links {
+		if l.Id == id {
+			link := db.Links[k]
+			return &link, nil
+		}
+	}
+
+	return &models.Link{}, fmt.Errorf("no link with id %v exists", id)
+}
+
+// CreateLink links two tasks. Both tasks must be out of the trash
+// and can only be linked once with a given type.
+func (db *InMemoryDatabase) CreateLink(l models.Link) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	for _, id := range []uint64{l.SourceId, l.TargetId} {
+		if _, ok := db.liveTaskIndex(id); !ok {
+			return 0, fmt.Errorf("error creating link: no task with id %v exists", id)
+		}
+	}
+	for _, v := range db.Links {
+		if v.SourceId == l.SourceId && v.TargetId == l.TargetId && v.Type == l.Type {
+			return 0, fmt.Errorf("error creating link: tasks %v and %v are already linked with %s", l.SourceId, l.TargetId, l.Type)
+		}
+	}
+
+	l.Id = db.nextLinkID
+	l.CreatedAt = time.Now()
+	db.nextLinkID++
+	db.Links = append(db.Links, l)
+
+	return l.Id, nil
+}
+
+func (db *InMemoryDatabase) DeleteLink(id uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	for k := range db.Links {
+		if db.Links[k].Id == id {
+			db.Links = append(db.Links[:k], db.Links[k+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error deleting link id %d: no link with id %v exists", id, id)
+}
+
+// removeTaskLinks removes the links of a task, from either side.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) removeTaskLinks(taskId uint64) {
+	links := db.Links[:0]
+	for _, l := range db.Links {
+		if l.SourceId != taskId && l.TargetId != taskId {
+			links = append(links, l)
+		}
+	}
+	db.Links = links
+}
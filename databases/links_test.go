@@ -0,0 +1,63 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinks(t *testing.T) {
+	db := NewInMemoryDatabase()
+	for i := 0; i < 3; i++ {
+		db.CreateTask(models.Task{Title: "Test Title"}, models.TaskCondition{})
+	}
+
+	t.Run("Create links", func(t *testing.T) {
+		id, err := db.CreateLink(models.Link{SourceId: 0, TargetId: 1, Type: "duplicates"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), id)
+
+		id, err = db.CreateLink(models.Link{SourceId: 2, TargetId: 0, Type: "relates_to"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+
+		l, err := db.GetLinkByID(0)
+		assert.NoError(t, err)
+		assert.False(t, l.CreatedAt.IsZero())
+	})
+
+	t.Run("Create invalid links", func(t *testing.T) {
+		_, err := db.CreateLink(models.Link{SourceId: 0, TargetId: 1, Type: "duplicates"})
+		assert.Error(t, err)
+		_, err = db.CreateLink(models.Link{SourceId: 0, TargetId: 99, Type: "duplicates"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Get task links from either side", func(t *testing.T) {
+		assert.Len(t, db.GetTaskLinks(0), 2)
+		assert.Len(t, db.GetTaskLinks(1), 1)
+		assert.Len(t, db.GetTaskLinks(2), 1)
+	})
+
+	t.Run("Links to trashed tasks are hidden and removed on purge", func(t *testing.T) {
+		db.DeleteTask(0)
+		assert.Empty(t, db.GetTaskLinks(1))
+		_, err := db.CreateLink(models.Link{SourceId: 1, TargetId: 0, Type: "caused_by"})
+		assert.Error(t, err)
+
+		db.RestoreTask(0)
+		assert.Len(t, db.GetTaskLinks(1), 1)
+
+		db.DeleteTask(0)
+		db.PurgeTask(0)
+		assert.Empty(t, db.Links)
+	})
+
+	t.Run("Delete link", func(t *testing.T) {
+		id, _ := db.CreateLink(models.Link{SourceId: 1, TargetId: 2, Type: "relates_to"})
+		assert.NoError(t, db.DeleteLink(id))
+		assert.Empty(t, db.GetTaskLinks(1))
+		assert.Error(t, db.DeleteLink(id))
+	})
+}
@@ -1,32 +1,117 @@
 package databases
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 	"todo-go/models"
+	"todo-go/search"
 )
 
 type InMemoryDatabase struct {
-	Tasks []models.Task
-	rwm   sync.RWMutex
+	Tasks    []models.Task
+	History  []models.HistoryEntry
+	Comments []models.Comment
+	Projects []models.Project
+	Worklogs []models.Worklog
+	Links    []models.Link
+	Views    []models.View
+	rwm      sync.RWMutex
+	index    *search.Index
+
+	nextID           uint64
+	nextCommentID    uint64
+	nextAttachmentID uint64
+	nextProjectID    uint64
+	nextWorklogID    uint64
+	nextChecklistID  uint64
+	nextLinkID       uint64
+	nextViewID       uint64
 }
 
 func NewInMemoryDatabase() *InMemoryDatabase {
 	return &InMemoryDatabase{
 		Tasks: make([]models.Task, 0),
+		index: search.NewIndex(),
 	}
 }
 
-func (db *InMemoryDatabase) GetTaskByID(id uint64) (*models.Task, error) {
-	db.rwm.RLock()
-	defer db.rwm.RUnlock()
+// Close releases the resources of the database.
+// The in-memory database holds none, there's nothing to flush.
+func (db *InMemoryDatabase) Close() error {
+	return nil
+}
+
+// Delay between attempts to take the read lock during health checks
+const healthCheckRetryInterval = 5 * time.Millisecond
+
+// HealthCheck checks the database can be read before the context is done,
+// ie: it isn't stuck behind a lock. It retries to take the read lock rather
+// than waiting for it, so that no goroutine is left blocked on a stuck lock.
+func (db *InMemoryDatabase) HealthCheck(ctx context.Context) error {
+	ticker := time.NewTicker(healthCheckRetryInterval)
+	defer ticker.Stop()
+
+	for !db.rwm.TryRLock() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database is locked: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	db.rwm.RUnlock()
+	return nil
+}
+
+// checkCondition checks the task at index k, -1 for a new task, still matches
+// the condition of a write putting it in the status. It must be called with the lock held.
+func (db *InMemoryDatabase) checkCondition(k int, status models.Status, c models.TaskCondition) error {
+	if k >= 0 && c.Status != "" && db.Tasks[k].Status != c.Status {
+		return fmt.Errorf("status is %s instead of %s: %w", db.Tasks[k].Status, c.Status, models.ErrTaskChanged)
+	}
+
+	if c.WIPLimit > 0 && (k < 0 || db.Tasks[k].Status != status) {
+		count := 0
+		for _, t := range db.Tasks {
+			if t.DeletedAt == nil && t.Status == status {
+				count++
+			}
+		}
+		if count >= c.WIPLimit {
+			return fmt.Errorf("status %s: %w", status, models.ErrWIPLimitReached)
+		}
+	}
+	return nil
+}
 
+// indexOf returns the index of the task with the given id, trashed or not.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) indexOf(id uint64) (int, bool) {
 	for k, v := range db.Tasks {
 		if v.Id == id {
-			return &db.Tasks[k], nil
+			return k, true
 		}
 	}
+	return -1, false
+}
+
+// liveTaskIndex returns the index of the task with the given id if it isn't in the trash.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) liveTaskIndex(id uint64) (int, bool) {
+	k, ok := db.indexOf(id)
+	return k, ok && db.Tasks[k].DeletedAt == nil
+}
+
+func (db *InMemoryDatabase) GetTaskByID(id uint64) (*models.Task, error) {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	if k, ok := db.indexOf(id); ok && db.Tasks[k].DeletedAt == nil {
+		return &db.Tasks[k], nil
+	}
 
 	return &models.Task{}, fmt.Errorf("no task with id %v exists", id)
 }
@@ -35,67 +120,225 @@ func (db *InMemoryDatabase) GetAllTasks() []models.Task {
 	db.rwm.RLock()
 	defer db.rwm.RUnlock()
 
-	return db.Tasks
+	tasks := make([]models.Task, 0, len(db.Tasks))
+	for _, v := range db.Tasks {
+		if v.DeletedAt == nil {
+			tasks = append(tasks, v)
+		}
+	}
+
+	return tasks
+}
+
+// FindTasks returns the tasks, out of the trash, matching the filter
+func (db *InMemoryDatabase) FindTasks(f models.TaskFilter) []models.Task {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	archived := make(map[uint64]bool)
+	if !f.IncludeArchived {
+		for _, p := range db.Projects {
+			if p.Archived {
+				archived[p.Id] = true
+			}
+		}
+	}
+
+	match := compileTaskQuery(f.Query)
+	tasks := make([]models.Task, 0)
+	for _, v := range db.Tasks {
+		if v.DeletedAt != nil || !matchFilter(v, f) || !match(&v) {
+			continue
+		}
+		if v.ProjectId != nil && archived[*v.ProjectId] {
+			continue
+		}
+		tasks = append(tasks, v)
+	}
+	if len(f.Sort) == 0 {
+		sortTasks(tasks, []models.SortKey{{Field: "position"}})
+	} else {
+		sortTasks(tasks, f.Sort)
+	}
+
+	return tasks
 }
 
-func (db *InMemoryDatabase) CreateTask(t models.Task) (uint64, error) {
+// sortTasks sorts the tasks by the keys, keeping the insertion order of equal tasks
+func sortTasks(tasks []models.Task, keys []models.SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		for _, k := range keys {
+			c := compareTasks(&tasks[i], &tasks[j], k.Field)
+			if c == 0 {
+				continue
+			}
+			if k.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// compareTasks compares the field of two tasks. It returns -1, 0 or +1.
+func compareTasks(a, b *models.Task, field string) int {
+	switch field {
+	case "id":
+		return compareUint(a.Id, b.Id)
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "priority":
+		return compareUint(uint64(a.Priority), uint64(b.Priority))
+	case "status":
+		return strings.Compare(string(a.Status), string(b.Status))
+	case "position":
+		return strings.Compare(a.Position, b.Position)
+	case "created_at":
+		return compareTime(a.CreatedAt, b.CreatedAt)
+	case "updated_at":
+		return compareTime(a.UpdatedAt, b.UpdatedAt)
+	}
+
+	if strings.HasPrefix(field, models.CustomFieldPrefix) {
+		name := strings.TrimPrefix(field, models.CustomFieldPrefix)
+		return models.CompareCustomValues(a.CustomFields[name], b.CustomFields[name])
+	}
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// matchFilter returns whether the task matches the filter
+func matchFilter(t models.Task, f models.TaskFilter) bool {
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, s := range f.Statuses {
+			if t.Status == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Priority != nil && t.Priority != *f.Priority {
+		return false
+	}
+	if f.ProjectId != nil && (t.ProjectId == nil || *t.ProjectId != *f.ProjectId) {
+		return false
+	}
+	for name, value := range f.CustomFields {
+		v, ok := t.CustomFields[name]
+		if !ok || models.FormatCustomValue(v) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (db *InMemoryDatabase) CreateTask(t models.Task, c models.TaskCondition) (uint64, error) {
 	db.rwm.Lock()
 	defer db.rwm.Unlock()
 
-	var id uint64
-	length := len(db.Tasks)
+	if err := db.checkCondition(-1, t.Status, c); err != nil {
+		return 0, fmt.Errorf("error creating task: %w", err)
+	}
+
+	// Ids are never reused, even after a task is purged.
 	// First id is 0
-	if length == 0 {
-		id = 0
-	} else {
-		id = uint64(length)
+	id := db.nextID
+	if length := len(db.Tasks); length > 0 && db.Tasks[length-1].Id >= id {
+		id = db.Tasks[length-1].Id + 1
+	}
+	db.nextID = id + 1
+
+	// New tasks go at the end of the list
+	position, err := models.RankAfter(db.lastPosition())
+	if err != nil {
+		return 0, fmt.Errorf("error creating task: %s", err.Error())
 	}
 
 	d := time.Now()
-	t.Id = uint64(id)
+	t.Id = id
+	t.Position = position
 	t.CreatedAt = d
 	t.UpdatedAt = d
+	t.DeletedAt = nil
+	t.BodyHTML = ""
+	t.Attachments = nil
+	t.Checklist = nil
+	t.ChecklistCompletion = nil
 	db.Tasks = append(db.Tasks, t)
+	db.indexTask(&t)
 
-	return uint64(id), nil
+	return id, nil
 }
 
-func (db *InMemoryDatabase) UpdateTask(t models.Task) error {
-	task, err := db.GetTaskByID(t.Id)
-	if err != nil {
-		return fmt.Errorf("error updating task id %d: %s", t.Id, err.Error())
-	}
-
+// UpdateTask updates the task, failing with models.ErrTaskChanged if the
+// stored task doesn't match c anymore
+func (db *InMemoryDatabase) UpdateTask(t models.Task, c models.TaskCondition) error {
 	db.rwm.Lock()
 	defer db.rwm.Unlock()
 
+	k, ok := db.indexOf(t.Id)
+	if !ok || db.Tasks[k].DeletedAt != nil {
+		return fmt.Errorf("error updating task id %d: no task with id %v exists", t.Id, t.Id)
+	}
+	if err := db.checkCondition(k, t.Status, c); err != nil {
+		return fmt.Errorf("error updating task id %d: %w", t.Id, err)
+	}
+
 	d := time.Now()
+	task := &db.Tasks[k]
 	task.Title = t.Title
 	task.Body = t.Body
 	task.Priority = t.Priority
 	task.Status = t.Status
+	task.ProjectId = t.ProjectId
+	task.CustomFields = t.CustomFields
+	task.EstimateMinutes = t.EstimateMinutes
 	task.UpdatedAt = d
+	db.indexTask(task)
 
 	return nil
 }
 
+// DeleteTask moves the task to the trash
 func (db *InMemoryDatabase) DeleteTask(id uint64) error {
-	_, err := db.GetTaskByID(id)
-	if err != nil {
-		return fmt.Errorf("error deleting task id %d: %s", id, err.Error())
-	}
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
 
-	for k, v := range db.Tasks {
-		if v.Id == id {
-			// Remove the element at index k
-			// https://github.com/golang/go/wiki/SliceTricks#delete
-			//s.Tasks = append(s.Tasks[:k], s.Tasks[k+1:]...)
-			empty := new(models.Task)
-			copy(db.Tasks[k:], db.Tasks[k+1:])
-			db.Tasks[len(db.Tasks)-1] = *empty
-			db.Tasks = db.Tasks[:len(db.Tasks)-1]
-		}
+	k, ok := db.indexOf(id)
+	if !ok || db.Tasks[k].DeletedAt != nil {
+		return fmt.Errorf("error deleting task id %d: no task with id %v exists", id, id)
 	}
 
+	d := time.Now()
+	db.Tasks[k].DeletedAt = &d
+
 	return nil
 }
@@ -1,8 +1,10 @@
 package databases
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
+	"runtime"
 	"testing"
 	"time"
 	"todo-go/models"
@@ -78,7 +80,7 @@ func TestStoreCreateTask(t *testing.T) {
 			Body:     fmt.Sprintf("Test body %d", newId),
 			Priority: models.Highest,
 			Status:   models.StatusInProgress,
-		})
+		}, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.Equal(t, id, newId)
 		assert.Equal(t, inMemoryDatabase.Tasks[id].CreatedAt, inMemoryDatabase.Tasks[id].UpdatedAt)
@@ -92,7 +94,7 @@ func TestStoreCreateTask(t *testing.T) {
 			Body:     fmt.Sprintf("Test body %d", newId),
 			Priority: models.Highest,
 			Status:   models.StatusInProgress,
-		})
+		}, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.Equal(t, id, newId)
 	})
@@ -105,7 +107,7 @@ func TestStoreCreateTask(t *testing.T) {
 			Body:     fmt.Sprintf("Test body %d", newId),
 			Priority: models.Highest,
 			Status:   models.StatusInProgress,
-		})
+		}, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.Equal(t, id, newId+1)
 	})
@@ -117,7 +119,7 @@ func TestStoreCreateTask(t *testing.T) {
 			Body:     "Test body",
 			Priority: models.Highest,
 			Status:   models.StatusInProgress,
-		})
+		}, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(0), id)
 
@@ -126,7 +128,7 @@ func TestStoreCreateTask(t *testing.T) {
 			Body:     "Test body",
 			Priority: models.Highest,
 			Status:   models.StatusInProgress,
-		})
+		}, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(1), id)
 	})
@@ -136,7 +138,7 @@ func TestStoreCreateTask(t *testing.T) {
 
 		id, err := inMemoryDatabase.CreateTask(models.Task{
 			CreatedAt: randomDate,
-		})
+		}, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.NotEqual(t, inMemoryDatabase.Tasks[id].CreatedAt, randomDate)
 	})
@@ -146,7 +148,7 @@ func TestStoreCreateTask(t *testing.T) {
 
 		id, err := inMemoryDatabase.CreateTask(models.Task{
 			CreatedAt: randomDate,
-		})
+		}, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.NotEqual(t, inMemoryDatabase.Tasks[id].UpdatedAt, randomDate)
 	})
@@ -162,7 +164,7 @@ func TestStoreUpdateTask(t *testing.T) {
 			Priority: models.Highest,
 			Status:   models.StatusInProgress,
 		}
-		err := inMemoryDatabase.UpdateTask(task)
+		err := inMemoryDatabase.UpdateTask(task, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(id), inMemoryDatabase.Tasks[id].Id)
 		assert.Equal(t, inMemoryDatabase.Tasks[id].Title, task.Title)
@@ -181,7 +183,7 @@ func TestStoreUpdateTask(t *testing.T) {
 			Priority: models.Highest,
 			Status:   models.StatusInProgress,
 		}
-		err := inMemoryDatabase.UpdateTask(task)
+		err := inMemoryDatabase.UpdateTask(task, models.TaskCondition{})
 		assert.Error(t, err)
 	})
 
@@ -196,7 +198,7 @@ func TestStoreUpdateTask(t *testing.T) {
 			Status:    models.StatusInProgress,
 			UpdatedAt: randomDate,
 		}
-		err := inMemoryDatabase.UpdateTask(task)
+		err := inMemoryDatabase.UpdateTask(task, models.TaskCondition{})
 		assert.NoError(t, err)
 		assert.Equal(t, uint64(id), inMemoryDatabase.Tasks[id].Id)
 		assert.Equal(t, inMemoryDatabase.Tasks[id].Title, task.Title)
@@ -206,6 +208,45 @@ func TestStoreUpdateTask(t *testing.T) {
 		assert.NotEqual(t, inMemoryDatabase.Tasks[id].UpdatedAt, inMemoryDatabase.Tasks[id].CreatedAt)
 		assert.NotEqual(t, inMemoryDatabase.Tasks[id].UpdatedAt, randomDate)
 	})
+
+	t.Run("Update task whose status changed", func(t *testing.T) {
+		id := uint64(0)
+		task := models.Task{Id: id, Title: "Stale title", Status: models.StatusDone}
+		err := inMemoryDatabase.UpdateTask(task, models.TaskCondition{Status: models.StatusToDo})
+		assert.ErrorIs(t, err, models.ErrTaskChanged)
+		assert.Equal(t, models.Status(models.StatusInProgress), inMemoryDatabase.Tasks[id].Status)
+		assert.Equal(t, "Updated title", inMemoryDatabase.Tasks[id].Title)
+
+		err = inMemoryDatabase.UpdateTask(task, models.TaskCondition{Status: models.StatusInProgress})
+		assert.NoError(t, err)
+		assert.Equal(t, models.Status(models.StatusDone), inMemoryDatabase.Tasks[id].Status)
+	})
+}
+
+func TestStoreTaskWIPLimit(t *testing.T) {
+	db := NewInMemoryDatabase()
+	limit := models.TaskCondition{WIPLimit: 1}
+	id, err := db.CreateTask(models.Task{Status: models.StatusInProgress}, limit)
+	assert.NoError(t, err)
+	db.CreateTask(models.Task{Status: models.StatusToDo}, limit)
+
+	t.Run("Create a task in a full status", func(t *testing.T) {
+		_, err := db.CreateTask(models.Task{Status: models.StatusInProgress}, limit)
+		assert.ErrorIs(t, err, models.ErrWIPLimitReached)
+		assert.Len(t, db.GetAllTasks(), 2)
+	})
+
+	t.Run("Update a task into a full status", func(t *testing.T) {
+		err := db.UpdateTask(models.Task{Id: id + 1, Status: models.StatusInProgress}, limit)
+		assert.ErrorIs(t, err, models.ErrWIPLimitReached)
+		task, _ := db.GetTaskByID(id + 1)
+		assert.Equal(t, models.Status(models.StatusToDo), task.Status)
+	})
+
+	t.Run("Update a task within a full status", func(t *testing.T) {
+		err := db.UpdateTask(models.Task{Id: id, Title: "Still in progress", Status: models.StatusInProgress}, limit)
+		assert.NoError(t, err)
+	})
 }
 
 func TestStoreDeleteTask(t *testing.T) {
@@ -222,6 +263,75 @@ func TestStoreDeleteTask(t *testing.T) {
 	})
 }
 
+func TestFindTasks(t *testing.T) {
+	db := NewInMemoryDatabase()
+	active, _ := db.CreateProject(models.Project{Name: "active"})
+	archived, _ := db.CreateProject(models.Project{Name: "archived"})
+	db.UpdateProject(models.Project{Id: archived, Name: "archived", Archived: true})
+
+	db.CreateTask(models.Task{Status: models.StatusToDo, Priority: models.High}, models.TaskCondition{})
+	db.CreateTask(models.Task{Status: models.StatusDone, Priority: models.High, ProjectId: &active}, models.TaskCondition{})
+	db.CreateTask(models.Task{Status: models.StatusToDo, Priority: models.Low, ProjectId: &archived}, models.TaskCondition{})
+	trashed, _ := db.CreateTask(models.Task{Status: models.StatusToDo}, models.TaskCondition{})
+	db.DeleteTask(trashed)
+
+	high := models.High
+	tests := []struct {
+		name   string
+		filter models.TaskFilter
+		ids    []uint64
+	}{
+		{"No filter hides archived projects", models.TaskFilter{}, []uint64{0, 1}},
+		{"Include archived projects", models.TaskFilter{IncludeArchived: true}, []uint64{0, 1, 2}},
+		{"By status", models.TaskFilter{Statuses: []models.Status{models.StatusToDo}, IncludeArchived: true}, []uint64{0, 2}},
+		{"By statuses", models.TaskFilter{Statuses: []models.Status{models.StatusToDo, models.StatusDone}}, []uint64{0, 1}},
+		{"By priority", models.TaskFilter{Priority: &high}, []uint64{0, 1}},
+		{"By project", models.TaskFilter{ProjectId: &active}, []uint64{1}},
+		{"By archived project", models.TaskFilter{ProjectId: &archived}, []uint64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := make([]uint64, 0)
+			for _, task := range db.FindTasks(tt.filter) {
+				ids = append(ids, task.Id)
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
+
+func TestFindTasksCustomFields(t *testing.T) {
+	db := NewInMemoryDatabase()
+	db.CreateTask(models.Task{Title: "b", Priority: models.Low, CustomFields: map[string]interface{}{"points": float64(3), "customer": "acme"}}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "a", Priority: models.High, CustomFields: map[string]interface{}{"points": float64(1), "done": true}}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "c", Priority: models.Low}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "d", Priority: models.High, CustomFields: map[string]interface{}{"points": float64(2.5), "customer": "acme"}}, models.TaskCondition{})
+
+	tests := []struct {
+		name   string
+		filter models.TaskFilter
+		ids    []uint64
+	}{
+		{"By custom string", models.TaskFilter{CustomFields: map[string]string{"customer": "acme"}}, []uint64{0, 3}},
+		{"By custom number", models.TaskFilter{CustomFields: map[string]string{"points": "2.5"}}, []uint64{3}},
+		{"By custom bool", models.TaskFilter{CustomFields: map[string]string{"done": "true"}}, []uint64{1}},
+		{"By unknown custom field", models.TaskFilter{CustomFields: map[string]string{"nope": ""}}, []uint64{}},
+		{"Sort by title", models.TaskFilter{Sort: []models.SortKey{{Field: "title"}}}, []uint64{1, 0, 2, 3}},
+		{"Sort by custom number", models.TaskFilter{Sort: []models.SortKey{{Field: "cf.points"}}}, []uint64{1, 3, 0, 2}},
+		{"Sort by custom number descending", models.TaskFilter{Sort: []models.SortKey{{Field: "cf.points", Desc: true}}}, []uint64{2, 0, 3, 1}},
+		{"Sort by several keys", models.TaskFilter{Sort: []models.SortKey{{Field: "priority", Desc: true}, {Field: "cf.points"}}}, []uint64{1, 3, 0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := make([]uint64, 0)
+			for _, task := range db.FindTasks(tt.filter) {
+				ids = append(ids, task.Id)
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
+
 func randomDate() time.Time {
 	// Generate a random between min and max
 	// ref: https://stackoverflow.com/questions/43495745/how-to-generate-random-date-in-go-lang/43497333
@@ -238,7 +348,7 @@ func benchmarkCreateTask(i int, b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		db.CreateTask(models.Task{
 			Title: fmt.Sprintf("Title %d", n),
-		})
+		}, models.TaskCondition{})
 	}
 }
 
@@ -253,7 +363,7 @@ func benchmarkGetTasks(i int, b *testing.B) {
 	db := NewInMemoryDatabase()
 
 	for n := 0; n < i; n++ {
-		_, _ = db.CreateTask(models.Task{})
+		_, _ = db.CreateTask(models.Task{}, models.TaskCondition{})
 	}
 
 	for n := 0; n < b.N; n++ {
@@ -267,3 +377,27 @@ func BenchmarkGetTasks3(b *testing.B)    { benchmarkGetTasks(3, b) }
 func BenchmarkGetTasks10(b *testing.B)   { benchmarkGetTasks(10, b) }
 func BenchmarkGetTasks100(b *testing.B)  { benchmarkGetTasks(100, b) }
 func BenchmarkGetTasks1000(b *testing.B) { benchmarkGetTasks(1000, b) }
+
+func TestHealthCheck(t *testing.T) {
+	db := NewInMemoryDatabase()
+	assert.NoError(t, db.HealthCheck(context.Background()))
+
+	// Failed checks don't leave goroutines waiting for the lock
+	goroutines := runtime.NumGoroutine()
+	db.rwm.Lock()
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		assert.ErrorIs(t, db.HealthCheck(ctx), context.DeadlineExceeded)
+		cancel()
+	}
+	assert.Equal(t, goroutines, runtime.NumGoroutine())
+
+	// The check passes again once the lock is released
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		db.rwm.Unlock()
+	}()
+	assert.NoError(t, db.HealthCheck(ctx))
+}
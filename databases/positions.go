@@ -0,0 +1,142 @@
+package databases
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"todo-go/models"
+)
+
+// lastPosition returns the highest position of the tasks, trashed ones included.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) lastPosition() string {
+	var last string
+	for _, t := range db.Tasks {
+		if t.Position > last {
+			last = t.Position
+		}
+	}
+	return last
+}
+
+// byPosition returns the indexes of the tasks, trashed ones included, sorted by position.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) byPosition() []int {
+	indexes := make([]int, len(db.Tasks))
+	for k := range db.Tasks {
+		indexes[k] = k
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return db.Tasks[indexes[i]].Position < db.Tasks[indexes[j]].Position
+	})
+	return indexes
+}
+
+// MoveTask moves a task right before or right after another task, and to
+// another status if the move sets one. Both changes are applied atomically.
+// Moving a task to a status holding as many tasks as the work in progress limit
+// of m.Condition fails with models.ErrWIPLimitReached, and moving a task whose
+// status isn't the one of m.Condition anymore fails with models.ErrTaskChanged.
+// It returns the moved task.
+func (db *InMemoryDatabase) MoveTask(id uint64, m models.TaskMove) (*models.Task, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.indexOf(id)
+	if !ok || db.Tasks[k].DeletedAt != nil {
+		return &models.Task{}, fmt.Errorf("error moving task id %d: no task with id %v exists", id, id)
+	}
+	if m.Before != nil && m.After != nil {
+		return &models.Task{}, fmt.Errorf("error moving task id %d: only one of before and after can be set", id)
+	}
+	if m.Before == nil && m.After == nil && m.Status == "" {
+		return &models.Task{}, fmt.Errorf("error moving task id %d: one of before, after and status must be set", id)
+	}
+
+	status := db.Tasks[k].Status
+	if m.Status != "" {
+		status = m.Status
+	}
+	if err := db.checkCondition(k, status, m.Condition); err != nil {
+		return &models.Task{}, fmt.Errorf("error moving task id %d: %w", id, err)
+	}
+
+	position := db.Tasks[k].Position
+	if m.Before != nil || m.After != nil {
+		var err error
+		if position, err = db.positionAround(k, m); err != nil {
+			return &models.Task{}, fmt.Errorf("error moving task id %d: %s", id, err.Error())
+		}
+	}
+
+	db.Tasks[k].Position = position
+	if m.Status != "" {
+		db.Tasks[k].Status = m.Status
+	}
+	db.Tasks[k].UpdatedAt = time.Now()
+
+	task := db.Tasks[k]
+	return &task, nil
+}
+
+// positionAround returns the position of the task at index k
+// once moved right before or right after the task targeted by m.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) positionAround(k int, m models.TaskMove) (string, error) {
+	target := m.Before
+	if target == nil {
+		target = m.After
+	}
+	if *target == db.Tasks[k].Id {
+		return "", fmt.Errorf("can't move a task relative to itself")
+	}
+
+	// Ordered tasks without the moved one. Trashed tasks are kept as neighbours
+	// so no task takes their position, and they keep their place when restored.
+	order := make([]int, 0)
+	for _, i := range db.byPosition() {
+		if i != k {
+			order = append(order, i)
+		}
+	}
+	t := -1
+	for i, v := range order {
+		if db.Tasks[v].Id == *target && db.Tasks[v].DeletedAt == nil {
+			t = i
+		}
+	}
+	if t < 0 {
+		return "", fmt.Errorf("no task with id %v exists", *target)
+	}
+
+	// Neighbours of the new position
+	var prev, next string
+	if m.Before != nil {
+		next = db.Tasks[order[t]].Position
+		if t > 0 {
+			prev = db.Tasks[order[t-1]].Position
+		}
+	} else {
+		prev = db.Tasks[order[t]].Position
+		if t < len(order)-1 {
+			next = db.Tasks[order[t+1]].Position
+		}
+	}
+
+	return models.RankBetween(prev, next)
+}
+
+// RebalancePositions spreads the positions of the tasks evenly,
+// keeping their order, so positions are as short as possible.
+// Trashed tasks are included so they keep their place when restored.
+func (db *InMemoryDatabase) RebalancePositions() error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	indexes := db.byPosition()
+	for i, position := range models.EvenRanks(len(indexes)) {
+		db.Tasks[indexes[i]].Position = position
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func positionOrder(db *InMemoryDatabase) []uint64 {
+	ids := make([]uint64, 0)
+	for _, t := range db.FindTasks(models.TaskFilter{}) {
+		ids = append(ids, t.Id)
+	}
+	return ids
+}
+
+func TestMoveTask(t *testing.T) {
+	db := NewInMemoryDatabase()
+	for i := 0; i < 4; i++ {
+		db.CreateTask(models.Task{}, models.TaskCondition{})
+	}
+	id := func(v uint64) *uint64 { return &v }
+
+	t.Run("New tasks are appended", func(t *testing.T) {
+		assert.Equal(t, []uint64{0, 1, 2, 3}, positionOrder(db))
+	})
+
+	t.Run("Move before", func(t *testing.T) {
+		task, err := db.MoveTask(3, models.TaskMove{Before: id(0)})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), task.Id)
+		assert.Equal(t, []uint64{3, 0, 1, 2}, positionOrder(db))
+
+		_, err = db.MoveTask(2, models.TaskMove{Before: id(1)})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{3, 0, 2, 1}, positionOrder(db))
+	})
+
+	t.Run("Move after", func(t *testing.T) {
+		_, err := db.MoveTask(3, models.TaskMove{After: id(1)})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{0, 2, 1, 3}, positionOrder(db))
+
+		_, err = db.MoveTask(0, models.TaskMove{After: id(2)})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{2, 0, 1, 3}, positionOrder(db))
+	})
+
+	t.Run("Invalid moves", func(t *testing.T) {
+		_, err := db.MoveTask(0, models.TaskMove{})
+		assert.Error(t, err)
+		_, err = db.MoveTask(0, models.TaskMove{Before: id(1), After: id(2)})
+		assert.Error(t, err)
+		_, err = db.MoveTask(0, models.TaskMove{Before: id(0)})
+		assert.Error(t, err)
+		_, err = db.MoveTask(0, models.TaskMove{Before: id(99)})
+		assert.Error(t, err)
+		_, err = db.MoveTask(99, models.TaskMove{Before: id(0)})
+		assert.Error(t, err)
+	})
+
+	t.Run("Rebalance keeps the order", func(t *testing.T) {
+		for i := 0; i < 50; i++ {
+			db.MoveTask(3, models.TaskMove{After: id(2)})
+			db.MoveTask(2, models.TaskMove{After: id(3)})
+		}
+		task, _ := db.GetTaskByID(2)
+		assert.Greater(t, len(task.Position), 10)
+
+		order := positionOrder(db)
+		assert.NoError(t, db.RebalancePositions())
+		assert.Equal(t, order, positionOrder(db))
+		for _, task := range db.GetAllTasks() {
+			assert.Len(t, task.Position, 1)
+		}
+	})
+}
+
+func TestMoveTaskAroundTrash(t *testing.T) {
+	db := NewInMemoryDatabase()
+	for i := 0; i < 3; i++ {
+		db.CreateTask(models.Task{}, models.TaskCondition{})
+	}
+	id := func(v uint64) *uint64 { return &v }
+	db.DeleteTask(1)
+
+	_, err := db.MoveTask(2, models.TaskMove{After: id(0)})
+	assert.NoError(t, err)
+	_, err = db.MoveTask(0, models.TaskMove{Before: id(1)})
+	assert.Error(t, err)
+
+	// The restored task keeps its place and shares its position with no other task
+	assert.NoError(t, db.RestoreTask(1))
+	assert.Equal(t, []uint64{0, 2, 1}, positionOrder(db))
+	positions := map[string]bool{}
+	for _, task := range db.GetAllTasks() {
+		assert.False(t, positions[task.Position])
+		positions[task.Position] = true
+	}
+}
+
+func TestMoveTaskStatus(t *testing.T) {
+	db := NewInMemoryDatabase()
+	for i := 0; i < 3; i++ {
+		db.CreateTask(models.Task{Status: models.StatusToDo}, models.TaskCondition{})
+	}
+	db.CreateTask(models.Task{Status: models.StatusInProgress}, models.TaskCondition{})
+	id := func(v uint64) *uint64 { return &v }
+
+	t.Run("Move to status and position", func(t *testing.T) {
+		task, err := db.MoveTask(0, models.TaskMove{Status: models.StatusInProgress, After: id(3), Condition: models.TaskCondition{WIPLimit: 2}})
+		assert.NoError(t, err)
+		assert.Equal(t, models.Status(models.StatusInProgress), task.Status)
+		assert.Equal(t, []uint64{1, 2, 3, 0}, positionOrder(db))
+	})
+
+	t.Run("Move to full status", func(t *testing.T) {
+		_, err := db.MoveTask(1, models.TaskMove{Status: models.StatusInProgress, Before: id(3), Condition: models.TaskCondition{WIPLimit: 2}})
+		assert.ErrorIs(t, err, models.ErrWIPLimitReached)
+
+		task, _ := db.GetTaskByID(1)
+		assert.Equal(t, models.Status(models.StatusToDo), task.Status)
+		assert.Equal(t, []uint64{1, 2, 3, 0}, positionOrder(db))
+	})
+
+	t.Run("Reorder within a full status", func(t *testing.T) {
+		_, err := db.MoveTask(0, models.TaskMove{Status: models.StatusInProgress, Before: id(3), Condition: models.TaskCondition{WIPLimit: 2}})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{1, 2, 0, 3}, positionOrder(db))
+	})
+
+	t.Run("Move to status keeping the position", func(t *testing.T) {
+		_, err := db.MoveTask(1, models.TaskMove{Status: models.StatusDone})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{1, 2, 0, 3}, positionOrder(db))
+	})
+	t.Run("Move a task whose status changed", func(t *testing.T) {
+		// Task 2 is in TODO, not in the status the move was checked against
+		m := models.TaskMove{Status: models.StatusDone, Condition: models.TaskCondition{Status: models.StatusInProgress}}
+		_, err := db.MoveTask(2, m)
+		assert.ErrorIs(t, err, models.ErrTaskChanged)
+		task, _ := db.GetTaskByID(2)
+		assert.Equal(t, models.Status(models.StatusToDo), task.Status)
+
+		m.Condition.Status = models.StatusToDo
+		_, err = db.MoveTask(2, m)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,105 @@
+package databases
+
+import (
+	"fmt"
+	"time"
+	"todo-go/models"
+)
+
+// projectIndexOf returns the index of the project with the given id.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) projectIndexOf(id uint64) (int, bool) {
+	for k, v := range db.Projects {
+		if v.Id == id {
+			return k, true
+		}
+	}
+	return -1, false
+}
+
+func (db *InMemoryDatabase) GetProjectByID(id uint64) (*models.Project, error) {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	if k, ok := db.projectIndexOf(id); ok {
+		p := db.Projects[k]
+		return &p, nil
+	}
+
+	return &models.Project{}, fmt.Errorf("no project with id %v exists", id)
+}
+
+func (db *InMemoryDatabase) GetAllProjects() []models.Project {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	projects := make([]models.Project, len(db.Projects))
+	copy(projects, db.Projects)
+
+	return projects
+}
+
+func (db *InMemoryDatabase) CreateProject(p models.Project) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	d := time.Now()
+	p.Id = db.nextProjectID
+	p.CreatedAt = d
+	p.UpdatedAt = d
+	db.nextProjectID++
+	db.Projects = append(db.Projects, p)
+
+	return p.Id, nil
+}
+
+// UpdateProject updates a project. Its custom fields can only change in ways
+// the values held by its tasks, including trashed ones, stay valid: otherwise
+// it fails with models.ErrCustomFieldsInUse.
+func (db *InMemoryDatabase) UpdateProject(p models.Project) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.projectIndexOf(p.Id)
+	if !ok {
+		return fmt.Errorf("error updating project id %d: no project with id %v exists", p.Id, p.Id)
+	}
+	for _, t := range db.Tasks {
+		if t.ProjectId == nil || *t.ProjectId != p.Id {
+			continue
+		}
+		if _, err := models.ValidateCustomFields(p.CustomFields, t.CustomFields); err != nil {
+			return fmt.Errorf("error updating project id %d: task id %d: %s: %w", p.Id, t.Id, err.Error(), models.ErrCustomFieldsInUse)
+		}
+	}
+
+	project := &db.Projects[k]
+	project.Name = p.Name
+	project.Description = p.Description
+	project.Archived = p.Archived
+	project.CustomFields = p.CustomFields
+	project.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// DeleteProject deletes a project. Projects still holding tasks,
+// including trashed ones, can't be deleted.
+func (db *InMemoryDatabase) DeleteProject(id uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.projectIndexOf(id)
+	if !ok {
+		return fmt.Errorf("error deleting project id %d: no project with id %v exists", id, id)
+	}
+	for _, t := range db.Tasks {
+		if t.ProjectId != nil && *t.ProjectId == id {
+			return fmt.Errorf("error deleting project id %d: project still holds tasks", id)
+		}
+	}
+
+	db.Projects = append(db.Projects[:k], db.Projects[k+1:]...)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjects(t *testing.T) {
+	db := NewInMemoryDatabase()
+
+	t.Run("Create projects", func(t *testing.T) {
+		id, err := db.CreateProject(models.Project{Name: "backend"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), id)
+		id, err = db.CreateProject(models.Project{Name: "frontend"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+
+		p, err := db.GetProjectByID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, "frontend", p.Name)
+		assert.Len(t, db.GetAllProjects(), 2)
+	})
+
+	t.Run("Get non existing project", func(t *testing.T) {
+		_, err := db.GetProjectByID(99)
+		assert.Error(t, err)
+	})
+
+	t.Run("Update project", func(t *testing.T) {
+		assert.NoError(t, db.UpdateProject(models.Project{Id: 1, Name: "web", Archived: true}))
+		p, _ := db.GetProjectByID(1)
+		assert.Equal(t, "web", p.Name)
+		assert.True(t, p.Archived)
+		assert.Error(t, db.UpdateProject(models.Project{Id: 99}))
+	})
+
+	t.Run("Update custom fields in use", func(t *testing.T) {
+		id := uint64(1)
+		fields := []models.CustomField{{Name: "points", Type: models.CustomFieldNumber}}
+		assert.NoError(t, db.UpdateProject(models.Project{Id: id, Name: "web", CustomFields: fields}))
+		taskID, _ := db.CreateTask(models.Task{ProjectId: &id, CustomFields: map[string]interface{}{"points": float64(3)}}, models.TaskCondition{})
+		db.DeleteTask(taskID)
+
+		// Trashed tasks can be restored, their values must stay valid too
+		err := db.UpdateProject(models.Project{Id: id, Name: "web"})
+		assert.ErrorIs(t, err, models.ErrCustomFieldsInUse)
+		p, _ := db.GetProjectByID(id)
+		assert.Equal(t, fields, p.CustomFields)
+
+		db.PurgeTask(taskID)
+		assert.NoError(t, db.UpdateProject(models.Project{Id: id, Name: "web"}))
+	})
+
+	t.Run("Delete project holding tasks", func(t *testing.T) {
+		id := uint64(0)
+		taskID, _ := db.CreateTask(models.Task{ProjectId: &id}, models.TaskCondition{})
+		db.DeleteTask(taskID)
+		assert.Error(t, db.DeleteProject(0))
+
+		db.PurgeTask(taskID)
+		assert.NoError(t, db.DeleteProject(0))
+		assert.Error(t, db.DeleteProject(0))
+	})
+}
@@ -0,0 +1,140 @@
+package databases
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"todo-go/models"
+	"todo-go/query"
+)
+
+// compileTaskQuery compiles a filter expression resolved by models.ParseTaskQuery
+// into a predicate on tasks. A nil expression matches every task.
+func compileTaskQuery(e query.Expr) func(t *models.Task) bool {
+	switch e := e.(type) {
+	case *query.And:
+		left, right := compileTaskQuery(e.Left), compileTaskQuery(e.Right)
+		return func(t *models.Task) bool { return left(t) && right(t) }
+	case *query.Or:
+		left, right := compileTaskQuery(e.Left), compileTaskQuery(e.Right)
+		return func(t *models.Task) bool { return left(t) || right(t) }
+	case *query.Not:
+		expr := compileTaskQuery(e.Expr)
+		return func(t *models.Task) bool { return !expr(t) }
+	case *query.Comparison:
+		return compileComparison(e)
+	}
+	return func(t *models.Task) bool { return true }
+}
+
+func compileComparison(c *query.Comparison) func(t *models.Task) bool {
+	switch c.Field {
+	case "text":
+		v := c.Resolved.(string)
+		return func(t *models.Task) bool {
+			return strings.Contains(strings.ToLower(t.Title), v) || strings.Contains(strings.ToLower(t.Body), v)
+		}
+	case "title":
+		return compileText(c, func(t *models.Task) string { return t.Title })
+	case "body":
+		return compileText(c, func(t *models.Task) string { return t.Body })
+	case "status":
+		v := c.Resolved.(models.Status)
+		return func(t *models.Task) bool { return compareOp(strings.Compare(string(t.Status), string(v)), c.Op) }
+	case "priority":
+		v := c.Resolved.(models.Priority)
+		return func(t *models.Task) bool { return compareOp(compareInts(int(t.Priority), int(v)), c.Op) }
+	case "project_id":
+		v := c.Resolved.(*uint64)
+		return func(t *models.Task) bool {
+			same := (t.ProjectId == nil && v == nil) || (t.ProjectId != nil && v != nil && *t.ProjectId == *v)
+			return same == (c.Op != query.OpNotEqual)
+		}
+	case "estimate_minutes":
+		v := c.Resolved.(int)
+		return func(t *models.Task) bool { return compareOp(compareInts(t.EstimateMinutes, v), c.Op) }
+	case "created_at":
+		return compileDate(c, func(t *models.Task) time.Time { return t.CreatedAt })
+	case "updated_at":
+		return compileDate(c, func(t *models.Task) time.Time { return t.UpdatedAt })
+	}
+
+	name := strings.TrimPrefix(c.Field, models.CustomFieldPrefix)
+	return func(t *models.Task) bool {
+		v, ok := t.CustomFields[name]
+		if !ok {
+			return c.Op == query.OpNotEqual
+		}
+		value, ok := customValue(v, c.Value)
+		if !ok {
+			return c.Op == query.OpNotEqual
+		}
+		return compareOp(models.CompareCustomValues(v, value), c.Op)
+	}
+}
+
+// compileText matches substrings with ":" and whole texts otherwise, ignoring case
+func compileText(c *query.Comparison, field func(t *models.Task) string) func(t *models.Task) bool {
+	v := c.Resolved.(string)
+	if c.Op == query.OpMatch {
+		return func(t *models.Task) bool { return strings.Contains(strings.ToLower(field(t)), v) }
+	}
+	return func(t *models.Task) bool { return compareOp(strings.Compare(strings.ToLower(field(t)), v), c.Op) }
+}
+
+// compileDate compares the day of the date field to the day of the comparison
+func compileDate(c *query.Comparison, field func(t *models.Task) time.Time) func(t *models.Task) bool {
+	day := c.Resolved.(time.Time)
+	next := day.AddDate(0, 0, 1)
+	return func(t *models.Task) bool {
+		d, r := field(t), 0
+		if d.Before(day) {
+			r = -1
+		} else if !d.Before(next) {
+			r = 1
+		}
+		return compareOp(r, c.Op)
+	}
+}
+
+// customValue converts the value of a comparison to the type of a custom field value
+func customValue(field interface{}, value string) (interface{}, bool) {
+	switch field.(type) {
+	case float64:
+		n, err := strconv.ParseFloat(value, 64)
+		return n, err == nil
+	case bool:
+		b, err := strconv.ParseBool(value)
+		return b, err == nil
+	}
+	return value, true
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareOp returns whether the result of a comparison satisfies the operator
+func compareOp(c int, op query.Op) bool {
+	switch op {
+	case query.OpMatch, query.OpEqual:
+		return c == 0
+	case query.OpNotEqual:
+		return c != 0
+	case query.OpLess:
+		return c < 0
+	case query.OpLessEqual:
+		return c <= 0
+	case query.OpGreater:
+		return c > 0
+	case query.OpGreaterEqual:
+		return c >= 0
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package databases
+
+import (
+	"testing"
+	"time"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTasksQuery(t *testing.T) {
+	db := NewInMemoryDatabase()
+	project, _ := db.CreateProject(models.Project{Name: "project"})
+
+	db.CreateTask(models.Task{Title: "Login page", Status: models.StatusToDo, Priority: models.High, EstimateMinutes: 30}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "Release", Body: "Mention the LOGIN fix", Status: models.StatusDone, Priority: models.Low, ProjectId: &project}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "Audit", Status: models.StatusToDo, Priority: models.Highest, ProjectId: &project,
+		CustomFields: map[string]interface{}{"points": float64(5), "due": "2026-11-15"}}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "Cleanup", Status: models.StatusBlocked, CustomFields: map[string]interface{}{"points": float64(1), "due": "2026-10-01"}}, models.TaskCondition{})
+
+	// Move the creation date of the last task to test dates
+	db.Tasks[3].CreatedAt = time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		filter string
+		ids    []uint64
+	}{
+		{`status:TODO`, []uint64{0, 2}},
+		{`status!=TODO`, []uint64{1, 3}},
+		{`priority>=High`, []uint64{0, 2}},
+		{`login`, []uint64{0, 1}},
+		{`title:login`, []uint64{0}},
+		{`title="login page"`, []uint64{0}},
+		{`project_id:none`, []uint64{0, 3}},
+		{`estimate_minutes>0`, []uint64{0}},
+		{`cf.points>2`, []uint64{2}},
+		{`cf.due<2026-11-01`, []uint64{3}},
+		{`cf.points!=5`, []uint64{0, 1, 3}},
+		{`created_at<=2026-01-31`, []uint64{3}},
+		{`created_at=2026-01-31`, []uint64{3}},
+		{`created_at<2026-01-31`, []uint64{}},
+		{`status:TODO priority>=High (cf.due<2026-12-01 OR NOT project_id:none)`, []uint64{2}},
+		{`status:DONE OR status:BLOCKED`, []uint64{1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			e, err := models.ParseTaskQuery(tt.filter)
+			assert.NoError(t, err)
+
+			ids := make([]uint64, 0)
+			for _, task := range db.FindTasks(models.TaskFilter{Query: e}) {
+				ids = append(ids, task.Id)
+			}
+			assert.Equal(t, tt.ids, ids)
+		})
+	}
+}
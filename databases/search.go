@@ -0,0 +1,45 @@
+package databases
+
+import (
+	"todo-go/models"
+	"todo-go/search"
+)
+
+// indexTask adds the task to the full-text index or updates it.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) indexTask(t *models.Task) {
+	if db.index == nil {
+		db.index = search.NewIndex()
+	}
+	db.index.Add(t.Id, t.Title, t.Body)
+}
+
+// unindexTask removes the task from the full-text index.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) unindexTask(id uint64) {
+	if db.index != nil {
+		db.index.Remove(id)
+	}
+}
+
+func (db *InMemoryDatabase) SearchTasks(q search.Query, limit int) []models.SearchResult {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	results := make([]models.SearchResult, 0)
+	if db.index == nil {
+		return results
+	}
+
+	// Tasks in the trash stay indexed so they are found again once restored
+	for _, hit := range db.index.Search(q) {
+		if len(results) >= limit {
+			break
+		}
+		if k, ok := db.liveTaskIndex(hit.Id); ok {
+			results = append(results, models.SearchResult{Task: db.Tasks[k], Score: hit.Score})
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,48 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+	"todo-go/search"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTasks(t *testing.T) {
+	db := NewInMemoryDatabase()
+	db.CreateTask(models.Task{Title: "Login page", Body: "Crashes on mobile"}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "Release notes", Body: "Mention the login fix"}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "Unrelated"}, models.TaskCondition{})
+
+	find := func(q string, limit int) []uint64 {
+		query, err := search.ParseQuery(q)
+		assert.NoError(t, err)
+		ids := make([]uint64, 0)
+		for _, r := range db.SearchTasks(query, limit) {
+			ids = append(ids, r.Task.Id)
+		}
+		return ids
+	}
+
+	t.Run("Search tasks", func(t *testing.T) {
+		assert.Equal(t, []uint64{0, 1}, find("login", 10))
+		assert.Equal(t, []uint64{0}, find("login", 1))
+	})
+
+	t.Run("Search updated tasks", func(t *testing.T) {
+		db.UpdateTask(models.Task{Id: 2, Title: "Login audit"}, models.TaskCondition{})
+		assert.Equal(t, []uint64{2, 0, 1}, find("login", 10))
+	})
+
+	t.Run("Search skips trashed tasks until restored", func(t *testing.T) {
+		db.DeleteTask(0)
+		assert.Equal(t, []uint64{2, 1}, find("login", 10))
+		db.RestoreTask(0)
+		assert.Equal(t, []uint64{2, 0, 1}, find("login", 10))
+	})
+
+	t.Run("Search skips purged tasks", func(t *testing.T) {
+		db.PurgeTask(0)
+		assert.Empty(t, find("mobile", 10))
+	})
+}
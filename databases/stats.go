@@ -0,0 +1,102 @@
+package databases
+
+import (
+	"time"
+	"todo-go/models"
+)
+
+// GetStats computes the statistics in a single pass over the tasks and their history
+func (db *InMemoryDatabase) GetStats(f models.StatsFilter) *models.Stats {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	days := f.Days()
+	s := &models.Stats{
+		From:       f.From.Format(time.DateOnly),
+		To:         f.To.AddDate(0, 0, -1).Format(time.DateOnly),
+		ByStatus:   make(map[models.Status]int),
+		ByPriority: make(map[string]int),
+		Throughput: make([]models.ThroughputDay, len(days)),
+		Burndown:   make([]models.BurndownDay, len(days)),
+	}
+	for k, d := range days {
+		s.Throughput[k].Date = d
+		s.Burndown[k].Date = d
+	}
+	// dayOf returns the index of the day of t in the range
+	dayOf := func(t time.Time) (int, bool) {
+		if t.Before(f.From) || !t.Before(f.To) {
+			return -1, false
+		}
+		return int(t.Sub(f.From) / (24 * time.Hour)), true
+	}
+
+	history := make(map[uint64][]models.HistoryEntry)
+	for _, e := range db.History {
+		history[e.TaskId] = append(history[e.TaskId], e)
+	}
+
+	var leadTime, cycleTime time.Duration
+	var cycles int
+	for _, t := range db.Tasks {
+		if t.DeletedAt != nil {
+			continue
+		}
+		if f.ProjectId != nil && (t.ProjectId == nil || *t.ProjectId != *f.ProjectId) {
+			continue
+		}
+
+		s.Total++
+		s.ByStatus[t.Status]++
+		s.ByPriority[models.PriorityName(t.Priority)]++
+		if k, ok := dayOf(t.CreatedAt); ok {
+			s.Throughput[k].Created++
+		}
+
+		timeline := models.StatusTimeline(t, history[t.Id])
+		for k, c := range timeline {
+			// Count every move to DONE, tasks reopened and completed again count twice
+			if c.Status != models.StatusDone || (k > 0 && timeline[k-1].Status == models.StatusDone) {
+				continue
+			}
+			if d, ok := dayOf(c.At); ok {
+				s.Throughput[d].Completed++
+			}
+		}
+
+		if started, completed, ok := models.Completion(timeline); ok {
+			if _, ok := dayOf(completed); ok {
+				s.Completed++
+				leadTime += completed.Sub(t.CreatedAt)
+				if !started.IsZero() {
+					cycles++
+					cycleTime += completed.Sub(started)
+				}
+			}
+		}
+
+		// Walk the days and the timeline together to find the status of the task at the end of each day
+		next, open, created := 0, false, false
+		for k := range days {
+			end := f.From.AddDate(0, 0, k+1)
+			for next < len(timeline) && timeline[next].At.Before(end) {
+				open, created = models.IsOpenStatus(timeline[next].Status), true
+				next++
+			}
+			if created && open {
+				s.Burndown[k].Open++
+			}
+		}
+	}
+
+	if s.Completed > 0 {
+		hours := leadTime.Hours() / float64(s.Completed)
+		s.AverageLeadTimeHours = &hours
+	}
+	if cycles > 0 {
+		hours := cycleTime.Hours() / float64(cycles)
+		s.AverageCycleTimeHours = &hours
+	}
+
+	return s
+}
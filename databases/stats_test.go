@@ -0,0 +1,84 @@
+package databases
+
+import (
+	"testing"
+	"time"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStats(t *testing.T) {
+	db := NewInMemoryDatabase()
+	day := func(d, h int) time.Time { return time.Date(2024, 1, d, h, 0, 0, 0, time.UTC) }
+	status := func(id uint64, at time.Time, s models.Status) {
+		db.AddHistoryEntry(models.HistoryEntry{TaskId: id, Timestamp: at, Changes: []models.FieldChange{{Field: "status", New: s}}})
+	}
+	project := uint64(1)
+
+	// Created on the 1st, in progress on the 2nd, done on the 3rd
+	db.CreateTask(models.Task{Title: "done", Status: models.StatusDone, Priority: models.High}, models.TaskCondition{})
+	db.Tasks[0].CreatedAt = day(1, 0)
+	status(0, day(1, 0), models.StatusToDo)
+	status(0, day(2, 0), models.StatusInProgress)
+	status(0, day(3, 0), models.StatusDone)
+
+	// Created on the 2nd and done on the 4th without being in progress
+	db.CreateTask(models.Task{Title: "skipped", Status: models.StatusDone, Priority: models.High, ProjectId: &project}, models.TaskCondition{})
+	db.Tasks[1].CreatedAt = day(2, 0)
+	status(1, day(2, 0), models.StatusToDo)
+	status(1, day(4, 0), models.StatusDone)
+
+	// Created on the 3rd, still to do
+	db.CreateTask(models.Task{Title: "open", Status: models.StatusToDo, Priority: models.Low}, models.TaskCondition{})
+	db.Tasks[2].CreatedAt = day(3, 12)
+	status(2, day(3, 12), models.StatusToDo)
+
+	// Without history, cancelled since its creation on the 2nd
+	db.CreateTask(models.Task{Title: "cancelled", Status: models.StatusCancelled, Priority: models.Low}, models.TaskCondition{})
+	db.Tasks[3].CreatedAt = day(2, 0)
+
+	// Tasks in the trash are left out
+	db.CreateTask(models.Task{Title: "trashed", Status: models.StatusToDo}, models.TaskCondition{})
+	db.Tasks[4].CreatedAt = day(2, 0)
+	db.DeleteTask(4)
+
+	f := models.StatsFilter{From: day(1, 0), To: day(5, 0)}
+	s := db.GetStats(f)
+	assert.Equal(t, "2024-01-01", s.From)
+	assert.Equal(t, "2024-01-04", s.To)
+	assert.Equal(t, 4, s.Total)
+	assert.Equal(t, map[models.Status]int{models.StatusDone: 2, models.StatusToDo: 1, models.StatusCancelled: 1}, s.ByStatus)
+	assert.Equal(t, map[string]int{"High": 2, "Low": 2}, s.ByPriority)
+
+	assert.Equal(t, 2, s.Completed)
+	assert.InDelta(t, 48, *s.AverageLeadTimeHours, 0.001)
+	assert.InDelta(t, 24, *s.AverageCycleTimeHours, 0.001)
+
+	assert.Equal(t, []models.ThroughputDay{
+		{Date: "2024-01-01", Created: 1},
+		{Date: "2024-01-02", Created: 2},
+		{Date: "2024-01-03", Created: 1, Completed: 1},
+		{Date: "2024-01-04", Completed: 1},
+	}, s.Throughput)
+	assert.Equal(t, []models.BurndownDay{
+		{Date: "2024-01-01", Open: 1},
+		{Date: "2024-01-02", Open: 2},
+		{Date: "2024-01-03", Open: 2},
+		{Date: "2024-01-04", Open: 1},
+	}, s.Burndown)
+
+	t.Run("Restricted to a project", func(t *testing.T) {
+		s := db.GetStats(models.StatsFilter{From: day(1, 0), To: day(5, 0), ProjectId: &project})
+		assert.Equal(t, 1, s.Total)
+		assert.Equal(t, 1, s.Completed)
+		assert.Nil(t, s.AverageCycleTimeHours)
+	})
+
+	t.Run("Completions out of the range", func(t *testing.T) {
+		s := db.GetStats(models.StatsFilter{From: day(5, 0), To: day(6, 0)})
+		assert.Equal(t, 0, s.Completed)
+		assert.Nil(t, s.AverageLeadTimeHours)
+		assert.Equal(t, []models.BurndownDay{{Date: "2024-01-05", Open: 1}}, s.Burndown)
+	})
+}
@@ -0,0 +1,89 @@
+package databases
+
+import (
+	"fmt"
+	"time"
+	"todo-go/models"
+)
+
+func (db *InMemoryDatabase) GetDeletedTasks() []models.Task {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	tasks := make([]models.Task, 0)
+	for _, v := range db.Tasks {
+		if v.DeletedAt != nil {
+			tasks = append(tasks, v)
+		}
+	}
+
+	return tasks
+}
+
+// RestoreTask moves the task out of the trash
+func (db *InMemoryDatabase) RestoreTask(id uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.indexOf(id)
+	if !ok || db.Tasks[k].DeletedAt == nil {
+		return fmt.Errorf("error restoring task id %d: no deleted task with id %v exists", id, id)
+	}
+
+	db.Tasks[k].DeletedAt = nil
+	db.Tasks[k].UpdatedAt = time.Now()
+
+	return nil
+}
+
+// PurgeTask permanently removes the task, whether it is in the trash or not.
+// Comments are kept while the task is in the trash and removed when it is purged.
+// It returns the purged task.
+func (db *InMemoryDatabase) PurgeTask(id uint64) (*models.Task, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.indexOf(id)
+	if !ok {
+		return &models.Task{}, fmt.Errorf("error purging task id %d: no task with id %v exists", id, id)
+	}
+	t := db.Tasks[k]
+	db.remove(k)
+
+	return &t, nil
+}
+
+// PurgeDeletedTasks permanently removes the tasks moved to the trash
+// before the given date. It returns the purged tasks.
+func (db *InMemoryDatabase) PurgeDeletedTasks(before time.Time) ([]models.Task, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	purged := make([]models.Task, 0)
+	for k := 0; k < len(db.Tasks); {
+		if d := db.Tasks[k].DeletedAt; d != nil && d.Before(before) {
+			purged = append(purged, db.Tasks[k])
+			db.remove(k)
+			continue
+		}
+		k++
+	}
+
+	return purged, nil
+}
+
+// remove removes the task at index k along with the records attached to it.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) remove(k int) {
+	db.removeTaskComments(db.Tasks[k].Id)
+	db.removeTaskWorklogs(db.Tasks[k].Id)
+	db.removeTaskLinks(db.Tasks[k].Id)
+	db.unindexTask(db.Tasks[k].Id)
+
+	// Remove the element at index k
+	// https://github.com/golang/go/wiki/SliceTricks#delete
+	empty := new(models.Task)
+	copy(db.Tasks[k:], db.Tasks[k+1:])
+	db.Tasks[len(db.Tasks)-1] = *empty
+	db.Tasks = db.Tasks[:len(db.Tasks)-1]
+}
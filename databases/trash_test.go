@@ -0,0 +1,94 @@
+package databases
+
+import (
+	"testing"
+	"time"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftDeleteTask(t *testing.T) {
+	db := NewInMemoryDatabase()
+	id, _ := db.CreateTask(models.Task{Title: "Test Title 0"}, models.TaskCondition{})
+	db.CreateTask(models.Task{Title: "Test Title 1"}, models.TaskCondition{})
+
+	t.Run("Deleted task is moved to the trash", func(t *testing.T) {
+		err := db.DeleteTask(id)
+		assert.NoError(t, err)
+
+		_, err = db.GetTaskByID(id)
+		assert.Error(t, err)
+		assert.Len(t, db.GetAllTasks(), 1)
+
+		trash := db.GetDeletedTasks()
+		assert.Len(t, trash, 1)
+		assert.Equal(t, id, trash[0].Id)
+		assert.NotNil(t, trash[0].DeletedAt)
+	})
+
+	t.Run("Trashed task can't be updated or deleted", func(t *testing.T) {
+		assert.Error(t, db.UpdateTask(models.Task{Id: id}, models.TaskCondition{}))
+		assert.Error(t, db.DeleteTask(id))
+	})
+
+	t.Run("Restore task", func(t *testing.T) {
+		err := db.RestoreTask(id)
+		assert.NoError(t, err)
+
+		task, err := db.GetTaskByID(id)
+		assert.NoError(t, err)
+		assert.Nil(t, task.DeletedAt)
+		assert.Empty(t, db.GetDeletedTasks())
+	})
+
+	t.Run("Restore task not in the trash", func(t *testing.T) {
+		assert.Error(t, db.RestoreTask(id))
+		assert.Error(t, db.RestoreTask(99))
+	})
+}
+
+func TestPurgeTask(t *testing.T) {
+	db := NewInMemoryDatabase()
+	id, _ := db.CreateTask(models.Task{Title: "Test Title 0"}, models.TaskCondition{})
+
+	t.Run("Purge task", func(t *testing.T) {
+		task, err := db.PurgeTask(id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, task.Id)
+		assert.Empty(t, db.GetAllTasks())
+		assert.Empty(t, db.GetDeletedTasks())
+	})
+
+	t.Run("Purge non existing task", func(t *testing.T) {
+		_, err := db.PurgeTask(id)
+		assert.Error(t, err)
+	})
+
+	t.Run("Ids are not reused after a purge", func(t *testing.T) {
+		newID, err := db.CreateTask(models.Task{Title: "Test Title 1"}, models.TaskCondition{})
+		assert.NoError(t, err)
+		assert.NotEqual(t, id, newID)
+	})
+}
+
+func TestPurgeDeletedTasks(t *testing.T) {
+	db := NewInMemoryDatabase()
+	for i := 0; i < 3; i++ {
+		db.CreateTask(models.Task{}, models.TaskCondition{})
+	}
+	db.DeleteTask(0)
+	db.DeleteTask(1)
+	old := time.Now().Add(-48 * time.Hour)
+	db.Tasks[0].DeletedAt = &old
+
+	t.Run("Purge tasks trashed before a date", func(t *testing.T) {
+		purged, err := db.PurgeDeletedTasks(time.Now().Add(-24 * time.Hour))
+		assert.NoError(t, err)
+		assert.Len(t, purged, 1)
+		assert.Equal(t, uint64(0), purged[0].Id)
+
+		assert.Len(t, db.GetDeletedTasks(), 1)
+		assert.Len(t, db.GetAllTasks(), 1)
+	})
+}
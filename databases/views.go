@@ -0,0 +1,92 @@
+package databases
+
+import (
+	"fmt"
+	"time"
+	"todo-go/models"
+)
+
+// viewIndexOf returns the index of the view with the given id.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) viewIndexOf(id uint64) (int, bool) {
+	for k, v := range db.Views {
+		if v.Id == id {
+			return k, true
+		}
+	}
+	return -1, false
+}
+
+func (db *InMemoryDatabase) GetViews(user string) []models.View {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	views := make([]models.View, 0)
+	for _, v := range db.Views {
+		if v.VisibleTo(user) {
+			views = append(views, v)
+		}
+	}
+
+	return views
+}
+
+func (db *InMemoryDatabase) GetViewByID(id uint64) (*models.View, error) {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	if k, ok := db.viewIndexOf(id); ok {
+		v := db.Views[k]
+		return &v, nil
+	}
+
+	return &models.View{}, fmt.Errorf("no view with id %v exists", id)
+}
+
+func (db *InMemoryDatabase) CreateView(v models.View) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	d := time.Now()
+	v.Id = db.nextViewID
+	v.CreatedAt = d
+	v.UpdatedAt = d
+	db.nextViewID++
+	db.Views = append(db.Views, v)
+
+	return v.Id, nil
+}
+
+// UpdateView updates the view. Its owner can't be changed.
+func (db *InMemoryDatabase) UpdateView(v models.View) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.viewIndexOf(v.Id)
+	if !ok {
+		return fmt.Errorf("error updating view id %d: no view with id %v exists", v.Id, v.Id)
+	}
+
+	view := &db.Views[k]
+	view.Name = v.Name
+	view.Shared = v.Shared
+	view.Filter = v.Filter
+	view.Sort = v.Sort
+	view.GroupBy = v.GroupBy
+	view.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (db *InMemoryDatabase) DeleteView(id uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	k, ok := db.viewIndexOf(id)
+	if !ok {
+		return fmt.Errorf("error deleting view id %d: no view with id %v exists", id, id)
+	}
+	db.Views = append(db.Views[:k], db.Views[k+1:]...)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package databases
+
+import (
+	"testing"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViews(t *testing.T) {
+	db := NewInMemoryDatabase()
+
+	t.Run("Create views", func(t *testing.T) {
+		id, err := db.CreateView(models.View{Name: "mine", Owner: "john", Filter: "status:TODO"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), id)
+
+		id, err = db.CreateView(models.View{Name: "team", Owner: "jane", Shared: true})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+
+		db.CreateView(models.View{Name: "private", Owner: "jane"})
+
+		v, err := db.GetViewByID(0)
+		assert.NoError(t, err)
+		assert.Equal(t, "mine", v.Name)
+		assert.False(t, v.CreatedAt.IsZero())
+	})
+
+	t.Run("Get views of a user", func(t *testing.T) {
+		views := db.GetViews("john")
+		assert.Len(t, views, 2)
+		assert.Equal(t, "mine", views[0].Name)
+		assert.Equal(t, "team", views[1].Name)
+
+		assert.Len(t, db.GetViews("jane"), 2)
+	})
+
+	t.Run("Update view", func(t *testing.T) {
+		assert.NoError(t, db.UpdateView(models.View{Id: 0, Name: "edited", Owner: "jane", Shared: true}))
+		v, _ := db.GetViewByID(0)
+		assert.Equal(t, "edited", v.Name)
+		assert.Equal(t, "john", v.Owner)
+		assert.Empty(t, v.Filter)
+		assert.True(t, v.Shared)
+
+		assert.Error(t, db.UpdateView(models.View{Id: 99}))
+	})
+
+	t.Run("Delete view", func(t *testing.T) {
+		assert.NoError(t, db.DeleteView(0))
+		_, err := db.GetViewByID(0)
+		assert.Error(t, err)
+		assert.Error(t, db.DeleteView(0))
+	})
+}
@@ -0,0 +1,142 @@
+package databases
+
+import (
+	"fmt"
+	"time"
+	"todo-go/models"
+)
+
+func (db *InMemoryDatabase) GetTaskWorklogs(taskId uint64) []models.Worklog {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	worklogs := make([]models.Worklog, 0)
+	for _, wl := range db.Worklogs {
+		if wl.TaskId == taskId {
+			worklogs = append(worklogs, wl)
+		}
+	}
+
+	return worklogs
+}
+
+func (db *InMemoryDatabase) FindWorklogs(f models.WorklogFilter) []models.Worklog {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	worklogs := make([]models.Worklog, 0)
+	for _, wl := range db.Worklogs {
+		if !f.From.IsZero() && wl.StartedAt.Before(f.From) {
+			continue
+		}
+		if !f.To.IsZero() && !wl.StartedAt.Before(f.To) {
+			continue
+		}
+		if f.User != "" && wl.User != f.User {
+			continue
+		}
+		worklogs = append(worklogs, wl)
+	}
+
+	return worklogs
+}
+
+func (db *InMemoryDatabase) GetWorklogByID(id uint64) (*models.Worklog, error) {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	for k, wl := range db.Worklogs {
+		if wl.Id == id {
+			worklog := db.Worklogs[k]
+			return &worklog, nil
+		}
+	}
+
+	return &models.Worklog{}, fmt.Errorf("no worklog with id %v exists", id)
+}
+
+// GetRunningWorklog returns the worklog of the timer the user is running, if any
+func (db *InMemoryDatabase) GetRunningWorklog(user string) (*models.Worklog, error) {
+	db.rwm.RLock()
+	defer db.rwm.RUnlock()
+
+	for k, wl := range db.Worklogs {
+		if wl.User == user && wl.Running() {
+			worklog := db.Worklogs[k]
+			return &worklog, nil
+		}
+	}
+
+	return &models.Worklog{}, fmt.Errorf("no timer running for %s", user)
+}
+
+// CreateWorklog adds a worklog to a task. Worklogs without end start a timer,
+// a user can only run one timer at a time.
+func (db *InMemoryDatabase) CreateWorklog(wl models.Worklog) (uint64, error) {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	if k, ok := db.indexOf(wl.TaskId); !ok || db.Tasks[k].DeletedAt != nil {
+		return 0, fmt.Errorf("error creating worklog: no task with id %v exists", wl.TaskId)
+	}
+	if wl.Running() {
+		for _, v := range db.Worklogs {
+			if v.User == wl.User && v.Running() {
+				return 0, fmt.Errorf("error creating worklog: %s is already running a timer on task %v: %w", wl.User, v.TaskId, models.ErrTimerRunning)
+			}
+		}
+	}
+
+	d := time.Now()
+	wl.Id = db.nextWorklogID
+	wl.CreatedAt = d
+	wl.UpdatedAt = d
+	db.nextWorklogID++
+	db.Worklogs = append(db.Worklogs, wl)
+
+	return wl.Id, nil
+}
+
+func (db *InMemoryDatabase) UpdateWorklog(wl models.Worklog) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	for k := range db.Worklogs {
+		if db.Worklogs[k].Id == wl.Id {
+			db.Worklogs[k].StartedAt = wl.StartedAt
+			db.Worklogs[k].EndedAt = wl.EndedAt
+			db.Worklogs[k].Minutes = wl.Minutes
+			db.Worklogs[k].Note = wl.Note
+			db.Worklogs[k].UpdatedAt = time.Now()
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error updating worklog id %d: no worklog with id %v exists", wl.Id, wl.Id)
+}
+
+func (db *InMemoryDatabase) DeleteWorklog(id uint64) error {
+	db.rwm.Lock()
+	defer db.rwm.Unlock()
+
+	for k := range db.Worklogs {
+		if db.Worklogs[k].Id == id {
+			db.Worklogs = append(db.Worklogs[:k], db.Worklogs[k+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error deleting worklog id %d: no worklog with id %v exists", id, id)
+}
+
+// removeTaskWorklogs removes the worklogs of a task.
+// It must be called with the lock held.
+func (db *InMemoryDatabase) removeTaskWorklogs(taskId uint64) {
+	worklogs := db.Worklogs[:0]
+	for _, wl := range db.Worklogs {
+		if wl.TaskId != taskId {
+			worklogs = append(worklogs, wl)
+		}
+	}
+	db.Worklogs = worklogs
+}
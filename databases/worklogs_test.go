@@ -0,0 +1,74 @@
+package databases
+
+import (
+	"testing"
+	"time"
+	"todo-go/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorklogs(t *testing.T) {
+	db := NewInMemoryDatabase()
+	taskID, _ := db.CreateTask(models.Task{Title: "Test Title 0"}, models.TaskCondition{})
+	otherID, _ := db.CreateTask(models.Task{Title: "Test Title 1"}, models.TaskCondition{})
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := day.Add(time.Hour)
+
+	t.Run("Create worklogs", func(t *testing.T) {
+		id, err := db.CreateWorklog(models.Worklog{TaskId: taskID, User: "john", StartedAt: day, EndedAt: &end, Minutes: 60})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), id)
+
+		id, err = db.CreateWorklog(models.Worklog{TaskId: otherID, User: "jane", StartedAt: day.AddDate(0, 0, 1), EndedAt: &end, Minutes: 60})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), id)
+
+		_, err = db.CreateWorklog(models.Worklog{TaskId: 99, User: "john"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Run one timer at a time", func(t *testing.T) {
+		_, err := db.GetRunningWorklog("john")
+		assert.Error(t, err)
+
+		id, err := db.CreateWorklog(models.Worklog{TaskId: taskID, User: "john", StartedAt: time.Now()})
+		assert.NoError(t, err)
+		_, err = db.CreateWorklog(models.Worklog{TaskId: otherID, User: "john", StartedAt: time.Now()})
+		assert.ErrorIs(t, err, models.ErrTimerRunning)
+
+		wl, err := db.GetRunningWorklog("john")
+		assert.NoError(t, err)
+		assert.Equal(t, id, wl.Id)
+
+		stop := time.Now()
+		wl.EndedAt = &stop
+		assert.NoError(t, db.UpdateWorklog(*wl))
+		_, err = db.GetRunningWorklog("john")
+		assert.Error(t, err)
+	})
+
+	t.Run("Find worklogs", func(t *testing.T) {
+		assert.Len(t, db.FindWorklogs(models.WorklogFilter{}), 3)
+		assert.Len(t, db.FindWorklogs(models.WorklogFilter{User: "jane"}), 1)
+
+		worklogs := db.FindWorklogs(models.WorklogFilter{From: day, To: day.AddDate(0, 0, 1)})
+		assert.Len(t, worklogs, 1)
+		assert.Equal(t, uint64(0), worklogs[0].Id)
+	})
+
+	t.Run("Worklogs are retained in the trash and removed on purge", func(t *testing.T) {
+		db.DeleteTask(taskID)
+		assert.Len(t, db.GetTaskWorklogs(taskID), 2)
+
+		db.PurgeTask(taskID)
+		assert.Empty(t, db.GetTaskWorklogs(taskID))
+		assert.Len(t, db.GetTaskWorklogs(otherID), 1)
+	})
+
+	t.Run("Delete worklog", func(t *testing.T) {
+		assert.NoError(t, db.DeleteWorklog(1))
+		assert.Empty(t, db.GetTaskWorklogs(otherID))
+		assert.Error(t, db.DeleteWorklog(1))
+	})
+}
@@ -0,0 +1,138 @@
+// Package logging logs the requests served by the application as structured logs
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	// RequestIDHeader identifies a request across services. It is generated
+	// when the caller doesn't send one, and echoed back in the response.
+	RequestIDHeader = "X-Request-ID"
+	// Request ids sent by callers longer than this are replaced
+	maxRequestIDLength = 128
+)
+
+// New returns a logger writing to w in the given format, json or text,
+// the records of the given level or above: debug, info, warn or error.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid level %s: must be one of debug, info, warn, error", level)
+	}
+	opts := &slog.HandlerOptions{Level: l}
+
+	switch format {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid format %s: must be one of json, text", format)
+	}
+}
+
+// newRequestID returns a random request id
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID returns whether a request id sent by a caller can be trusted
+// to be logged: not empty, not too long and made of printable ASCII characters
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	return strings.IndexFunc(id, func(r rune) bool { return r <= ' ' || r > '~' }) == -1
+}
+
+// responseRecorder records the status code and the size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	code  int
+	bytes int
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Middleware logs the requests served by the routes of a mux.Router, see mux.Router.Use.
+// It makes sure every request has a request id, set on the request for the handlers
+// and on the response for the caller. Requests to quietRoutes, path templates
+// like "/healthz", are logged at the debug level.
+func Middleware(logger *slog.Logger, quietRoutes ...string) mux.MiddlewareFunc {
+	quiet := make(map[string]bool, len(quietRoutes))
+	for _, route := range quietRoutes {
+		quiet[route] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+				r.Header.Set(RequestIDHeader, id)
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			route := r.URL.Path
+			if cr := mux.CurrentRoute(r); cr != nil {
+				if tpl, err := cr.GetPathTemplate(); err == nil {
+					route = tpl
+				}
+			}
+
+			start := time.Now()
+			rec := &responseRecorder{ResponseWriter: w, code: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			level := slog.LevelInfo
+			if quiet[route] {
+				level = slog.LevelDebug
+			}
+			attrs := []slog.Attr{
+				slog.String("request_id", id),
+				slog.String("method", r.Method),
+				slog.String("route", route),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.code),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.Int("bytes", rec.bytes),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+			}
+			logger.LogAttrs(r.Context(), level, "request", attrs...)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", FormatJSON)
+	assert.NoError(t, err)
+	logger.Info("ignored")
+	logger.Warn("logged", "key", "value")
+	assert.NotContains(t, buf.String(), "ignored")
+	assert.Contains(t, buf.String(), `"msg":"logged","key":"value"`)
+
+	buf.Reset()
+	logger, err = New(&buf, "DEBUG", FormatText)
+	assert.NoError(t, err)
+	logger.Debug("logged")
+	assert.Contains(t, buf.String(), "level=DEBUG msg=logged")
+
+	_, err = New(&buf, "verbose", FormatJSON)
+	assert.Error(t, err)
+	_, err = New(&buf, "info", "xml")
+	assert.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := New(&buf, "info", FormatJSON)
+
+	var handledID string
+	r := mux.NewRouter()
+	r.Use(Middleware(logger, "/healthz"))
+	r.HandleFunc("/task/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		handledID = r.Header.Get(RequestIDHeader)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+	r.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+
+	serve := func(path, requestID string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		buf.Reset()
+		req, _ := http.NewRequest("POST", path, nil)
+		if requestID != "" {
+			req.Header.Set(RequestIDHeader, requestID)
+		}
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+
+		var record map[string]interface{}
+		if buf.Len() > 0 {
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		}
+		return res, record
+	}
+
+	t.Run("Log a request", func(t *testing.T) {
+		res, record := serve("/task/12", "")
+		id := res.Header().Get(RequestIDHeader)
+		assert.Len(t, id, 32)
+		assert.Equal(t, id, handledID)
+
+		assert.Equal(t, "INFO", record["level"])
+		assert.Equal(t, "request", record["msg"])
+		assert.Equal(t, id, record["request_id"])
+		assert.Equal(t, "POST", record["method"])
+		assert.Equal(t, "/task/{id:[0-9]+}", record["route"])
+		assert.Equal(t, "/task/12", record["path"])
+		assert.Equal(t, float64(http.StatusCreated), record["status"])
+		assert.Equal(t, float64(5), record["bytes"])
+		assert.Contains(t, record, "latency_ms")
+	})
+
+	t.Run("Propagate the request id", func(t *testing.T) {
+		res, record := serve("/task/12", "upstream-id")
+		assert.Equal(t, "upstream-id", res.Header().Get(RequestIDHeader))
+		assert.Equal(t, "upstream-id", handledID)
+		assert.Equal(t, "upstream-id", record["request_id"])
+	})
+
+	t.Run("Replace invalid request ids", func(t *testing.T) {
+		for _, id := range []string{"with space", "new\nline", strings.Repeat("a", 129)} {
+			res, _ := serve("/task/12", id)
+			assert.NotEqual(t, id, res.Header().Get(RequestIDHeader))
+			assert.Len(t, handledID, 32)
+		}
+	})
+
+	t.Run("Quiet routes are logged at debug level", func(t *testing.T) {
+		res, record := serve("/healthz", "")
+		assert.NotEmpty(t, res.Header().Get(RequestIDHeader))
+		assert.Nil(t, record)
+	})
+}
@@ -1,33 +1,316 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"todo-go/blobstores"
+	"todo-go/certs"
 	"todo-go/config"
 	"todo-go/controllers"
+	"todo-go/cors"
 	"todo-go/databases"
+	"todo-go/logging"
+	"todo-go/markdown"
+	"todo-go/metrics"
+	"todo-go/models"
+	"todo-go/ratelimit"
+	"todo-go/tracing"
 
-	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
 )
 
 func main() {
 	cfg := config.New()
 	r := mux.NewRouter()
 
+	logger, err := logging.New(os.Stdout, cfg.GetString("LOG_LEVEL"), cfg.GetString("LOG_FORMAT"))
+	if err != nil {
+		log.Fatal("Invalid logging: ", err)
+	}
+	// The standard logger writes through the structured logger too
+	slog.SetDefault(logger)
+
+	tp, err := tracing.NewTracerProvider(context.Background(), tracing.Options{
+		ServiceName: config.AppName,
+		Exporter:    cfg.GetString("TRACING_EXPORTER"),
+		Endpoint:    cfg.GetString("TRACING_OTLP_ENDPOINT"),
+		Insecure:    cfg.GetBool("TRACING_OTLP_INSECURE"),
+		SampleRatio: cfg.GetFloat64("TRACING_SAMPLE_RATIO"),
+	})
+	if err != nil {
+		log.Fatal("Invalid tracing: ", err)
+	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(tracing.Propagator())
+	r.Use(tracing.Middleware(config.AppName, tp))
+
+	reg := metrics.NewRegistry()
+	r.Use(metrics.NewHTTPMetrics(reg).Middleware)
+	// Probes and metrics are called every few seconds, they're only logged at the debug level
+	r.Use(logging.Middleware(logger, "/healthz", "/readyz", "/metrics"))
+
+	limiterOpts := []ratelimit.Option{
+		ratelimit.WithAPIKeyHeader(cfg.GetString("RATELIMIT_API_KEY_HEADER")),
+		ratelimit.WithAPIKeys(strings.Split(cfg.GetString("RATELIMIT_API_KEYS"), ",")),
+		ratelimit.WithTrustedProxy(cfg.GetBool("RATELIMIT_TRUST_PROXY")),
+	}
+	if v := cfg.GetString("RATELIMIT_DEFAULT"); v != "" {
+		l, err := ratelimit.ParseLimit(v)
+		if err != nil {
+			log.Fatal("Invalid rate limit: ", err)
+		}
+		limiterOpts = append(limiterOpts, ratelimit.WithDefaultLimit(&l))
+	}
+	routeLimits, err := ratelimit.ParseRouteLimits(cfg.GetString("RATELIMIT_ROUTES"))
+	if err != nil {
+		log.Fatal("Invalid rate limit: ", err)
+	}
+	limiterOpts = append(limiterOpts, ratelimit.WithRouteLimits(routeLimits))
+
+	var limiterStore ratelimit.Store
+	var redisClient *redis.Client
+	if cfg.GetString("RATELIMIT_STORE") == "memory" {
+		limiterStore = ratelimit.NewMemoryStore()
+	} else if cfg.GetString("RATELIMIT_STORE") == "redis" {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     cfg.GetString("RATELIMIT_REDIS_ADDR"),
+			Password: cfg.GetString("RATELIMIT_REDIS_PASSWORD"),
+			DB:       cfg.GetInt("RATELIMIT_REDIS_DB"),
+		})
+		limiterStore = ratelimit.NewRedisStore(redisClient, config.AppName+":ratelimit:")
+	} else {
+		log.Fatal("Invalid RATELIMIT_STORE. Must be one of 'memory', 'redis'")
+	}
+	r.Use(ratelimit.NewLimiter(limiterStore, limiterOpts...).Middleware)
+
+	wf, err := models.ParseWorkflow(cfg.GetString("WORKFLOW_STATUSES"), cfg.GetString("WORKFLOW_TRANSITIONS"))
+	if err != nil {
+		log.Fatal("Invalid workflow: ", err)
+	}
+
+	wipLimits, err := models.ParseWIPLimits(cfg.GetString("BOARD_WIP_LIMITS"))
+	if err != nil {
+		log.Fatal("Invalid board: ", err)
+	}
+
+	linkTypes, err := models.ParseLinkTypes(cfg.GetString("LINK_TYPES"))
+	if err != nil {
+		log.Fatal("Invalid link types: ", err)
+	}
+
+	var blobStore models.BlobStore
+	if cfg.GetString("BLOBSTORE_TYPE") == "local" {
+		blobStore, err = blobstores.NewLocalBlobStore(cfg.GetString("BLOBSTORE_LOCAL_PATH"))
+	} else if cfg.GetString("BLOBSTORE_TYPE") == "s3" {
+		blobStore, err = blobstores.NewS3BlobStore(blobstores.S3Options{
+			Endpoint:  cfg.GetString("BLOBSTORE_S3_ENDPOINT"),
+			Bucket:    cfg.GetString("BLOBSTORE_S3_BUCKET"),
+			Region:    cfg.GetString("BLOBSTORE_S3_REGION"),
+			AccessKey: cfg.GetString("BLOBSTORE_S3_ACCESS_KEY"),
+			SecretKey: cfg.GetString("BLOBSTORE_S3_SECRET_KEY"),
+			UseSSL:    cfg.GetBool("BLOBSTORE_S3_USE_SSL"),
+		})
+	} else {
+		log.Fatal("Invalid BLOBSTORE_TYPE. Must be one of 'local', 's3'")
+	}
+	if err != nil {
+		log.Fatal("Invalid blob store: ", err)
+	}
+
 	var h *controllers.BaseHandler
+	var database io.Closer
 	if cfg.GetString("DATABASE_TYPE") == "memory" {
-		h = controllers.NewBaseHandler(databases.NewInMemoryDatabase())
+		db := databases.NewInMemoryDatabase()
+		database = db
+		h = controllers.NewBaseHandler(tracing.NewTaskRepository(metrics.NewTaskRepository(db, reg), tp),
+			controllers.WithWorkflow(wf),
+			controllers.WithHistoryRepository(db),
+			controllers.WithCommentRepository(db),
+			controllers.WithProjectRepository(db),
+			controllers.WithWorklogRepository(db),
+			controllers.WithLinkRepository(db),
+			controllers.WithSearchRepository(db),
+			controllers.WithViewRepository(db),
+			controllers.WithStatsRepository(db),
+			controllers.WithLinkTypes(linkTypes),
+			controllers.WithMarkdownRenderer(markdown.NewRenderer(cfg.GetString("MARKDOWN_TASK_URL"), cfg.GetString("MARKDOWN_MENTION_URL"))),
+			controllers.WithWIPLimits(wipLimits),
+			controllers.WithChecklistAutoDone(cfg.GetBool("CHECKLIST_AUTO_DONE")),
+			controllers.WithMaxPositionLength(cfg.GetInt("POSITION_MAX_LENGTH")),
+			controllers.WithAttachments(blobStore, cfg.GetInt64("ATTACHMENTS_MAX_SIZE"), strings.Split(cfg.GetString("ATTACHMENTS_ALLOWED_TYPES"), ",")),
+		)
 	} else {
 		log.Fatal("Invalid DATABASE_TYPE. Must be one of 'memory'")
 	}
 
-	r.Handle("/", handlers.LoggingHandler(os.Stdout, http.HandlerFunc(h.RootHandler))).Methods("GET")
-	r.Handle("/tasks", handlers.LoggingHandler(os.Stdout, http.HandlerFunc(h.GetTasks))).Methods("GET")
-	r.Handle("/task", handlers.LoggingHandler(os.Stdout, http.HandlerFunc(h.CreateTask))).Methods("POST")
-	r.Handle("/task/{id:[0-9]+}", handlers.LoggingHandler(os.Stdout, http.HandlerFunc(h.GetTaskByID))).Methods("GET")
-	r.Handle("/task/{id:[0-9]+}", handlers.LoggingHandler(os.Stdout, http.HandlerFunc(h.UpdateTask))).Methods("PUT")
-	r.Handle("/task/{id:[0-9]+}", handlers.LoggingHandler(os.Stdout, http.HandlerFunc(h.DeleteTask))).Methods("DELETE")
-	log.Fatal(http.ListenAndServe(cfg.GetString("APP_ADDR"), r))
+	// Stop on SIGINT and SIGTERM, eg: during a rollout
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.GetDuration("TRASH_PURGE_INTERVAL") <= 0 {
+		log.Print("Trash purger disabled: TRASH_PURGE_INTERVAL is not positive")
+	}
+	purger := make(chan struct{})
+	go func() {
+		defer close(purger)
+		h.RunTrashPurger(ctx, cfg.GetDuration("TRASH_RETENTION"), cfg.GetDuration("TRASH_PURGE_INTERVAL"))
+	}()
+
+	r.HandleFunc("/healthz", h.Healthz).Methods("GET")
+	r.HandleFunc("/readyz", h.Readyz).Methods("GET")
+	r.Handle("/metrics", metrics.Handler(reg)).Methods("GET")
+	r.HandleFunc("/", h.RootHandler).Methods("GET")
+	r.HandleFunc("/tasks", h.GetTasks).Methods("GET")
+	r.HandleFunc("/search", h.SearchTasks).Methods("GET")
+	r.HandleFunc("/task", h.CreateTask).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}", h.GetTaskByID).Methods("GET")
+	r.HandleFunc("/task/{id:[0-9]+}", h.UpdateTask).Methods("PUT")
+	r.HandleFunc("/task/{id:[0-9]+}", h.DeleteTask).Methods("DELETE")
+	r.HandleFunc("/task/{id:[0-9]+}/history", h.GetTaskHistory).Methods("GET")
+	r.HandleFunc("/task/{id:[0-9]+}/attachments", h.UploadAttachment).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/attachments/{attachmentId:[0-9]+}", h.GetAttachment).Methods("GET")
+	r.HandleFunc("/task/{id:[0-9]+}/attachments/{attachmentId:[0-9]+}", h.DeleteAttachment).Methods("DELETE")
+	r.HandleFunc("/task/{id:[0-9]+}/comments", h.GetTaskComments).Methods("GET")
+	r.HandleFunc("/task/{id:[0-9]+}/comments", h.CreateComment).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/comments/{commentId:[0-9]+}", h.UpdateComment).Methods("PUT")
+	r.HandleFunc("/task/{id:[0-9]+}/comments/{commentId:[0-9]+}", h.DeleteComment).Methods("DELETE")
+	r.HandleFunc("/task/{id:[0-9]+}/checklist", h.AddChecklistItem).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/checklist/{itemId:[0-9]+}", h.DeleteChecklistItem).Methods("DELETE")
+	r.HandleFunc("/task/{id:[0-9]+}/checklist/{itemId:[0-9]+}/toggle", h.ToggleChecklistItem).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/checklist/{itemId:[0-9]+}/move", h.MoveChecklistItem).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/links", h.GetTaskLinks).Methods("GET")
+	r.HandleFunc("/task/{id:[0-9]+}/links", h.CreateLink).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/links/{linkId:[0-9]+}", h.DeleteLink).Methods("DELETE")
+	r.HandleFunc("/task/{id:[0-9]+}/worklogs", h.GetTaskWorklogs).Methods("GET")
+	r.HandleFunc("/task/{id:[0-9]+}/worklogs", h.CreateWorklog).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/worklogs/{worklogId:[0-9]+}", h.UpdateWorklog).Methods("PUT")
+	r.HandleFunc("/task/{id:[0-9]+}/worklogs/{worklogId:[0-9]+}", h.DeleteWorklog).Methods("DELETE")
+	r.HandleFunc("/task/{id:[0-9]+}/timer/start", h.StartTimer).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/timer/stop", h.StopTimer).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/move", h.MoveTask).Methods("POST")
+	r.HandleFunc("/task/{id:[0-9]+}/restore", h.RestoreTask).Methods("POST")
+	r.HandleFunc("/trash", h.GetTrash).Methods("GET")
+	r.HandleFunc("/projects", h.GetProjects).Methods("GET")
+	r.HandleFunc("/projects", h.CreateProject).Methods("POST")
+	r.HandleFunc("/projects/{id:[0-9]+}", h.GetProjectByID).Methods("GET")
+	r.HandleFunc("/projects/{id:[0-9]+}", h.UpdateProject).Methods("PUT")
+	r.HandleFunc("/projects/{id:[0-9]+}", h.DeleteProject).Methods("DELETE")
+	r.HandleFunc("/projects/{id:[0-9]+}/tasks", h.GetProjectTasks).Methods("GET")
+	r.HandleFunc("/reports/time", h.GetTimeReport).Methods("GET")
+	r.HandleFunc("/stats", h.GetStats).Methods("GET")
+	r.HandleFunc("/views", h.GetViews).Methods("GET")
+	r.HandleFunc("/views", h.CreateView).Methods("POST")
+	r.HandleFunc("/views/{id:[0-9]+}", h.GetViewByID).Methods("GET")
+	r.HandleFunc("/views/{id:[0-9]+}", h.UpdateView).Methods("PUT")
+	r.HandleFunc("/views/{id:[0-9]+}", h.DeleteView).Methods("DELETE")
+	r.HandleFunc("/views/{id:[0-9]+}/tasks", h.GetViewTasks).Methods("GET")
+	r.HandleFunc("/board", h.GetBoard).Methods("GET")
+	r.HandleFunc("/workflow", h.GetWorkflow).Methods("GET")
+
+	// CORS wraps the router to answer the preflight requests of every route
+	var handler http.Handler = r
+	if origins := cors.Split(cfg.GetString("CORS_ALLOWED_ORIGINS")); len(origins) > 0 {
+		corsHandler, err := cors.Handler(cors.Options{
+			AllowedOrigins:   origins,
+			AllowedMethods:   cors.Split(cfg.GetString("CORS_ALLOWED_METHODS")),
+			AllowedHeaders:   cors.Split(cfg.GetString("CORS_ALLOWED_HEADERS")),
+			ExposedHeaders:   cors.Split(cfg.GetString("CORS_EXPOSED_HEADERS")),
+			AllowCredentials: cfg.GetBool("CORS_ALLOW_CREDENTIALS"),
+			MaxAge:           cfg.GetDuration("CORS_MAX_AGE"),
+		})
+		if err != nil {
+			log.Fatal("Invalid CORS: ", err)
+		}
+		handler = corsHandler(r)
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.GetString("APP_ADDR"),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.GetDuration("SERVER_READ_HEADER_TIMEOUT"),
+		ReadTimeout:       cfg.GetDuration("SERVER_READ_TIMEOUT"),
+		WriteTimeout:      cfg.GetDuration("SERVER_WRITE_TIMEOUT"),
+		IdleTimeout:       cfg.GetDuration("SERVER_IDLE_TIMEOUT"),
+	}
+	serverErr := make(chan error, 1)
+	// Setting only one of the files is an error rather than a fallback to plain HTTP
+	if cfg.GetString("TLS_CERT_FILE") != "" || cfg.GetString("TLS_KEY_FILE") != "" {
+		clientAuth, err := certs.ParseClientAuth(cfg.GetString("TLS_CLIENT_AUTH"))
+		if err != nil {
+			log.Fatal("Invalid TLS: ", err)
+		}
+		reloader, err := certs.NewReloader(certs.Options{
+			CertFile:     cfg.GetString("TLS_CERT_FILE"),
+			KeyFile:      cfg.GetString("TLS_KEY_FILE"),
+			ClientCAFile: cfg.GetString("TLS_CLIENT_CA_FILE"),
+			ClientAuth:   clientAuth,
+		})
+		if err != nil {
+			log.Fatal("Invalid TLS: ", err)
+		}
+		if cfg.GetDuration("TLS_RELOAD_INTERVAL") <= 0 {
+			log.Print("TLS certificates reloading disabled: TLS_RELOAD_INTERVAL is not positive")
+		}
+		go reloader.Run(ctx, cfg.GetDuration("TLS_RELOAD_INTERVAL"))
+
+		srv.TLSConfig = reloader.TLSConfig()
+		go func() {
+			serverErr <- srv.ListenAndServeTLS("", "")
+		}()
+	} else {
+		go func() {
+			serverErr <- srv.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting for the shutdown
+	stop()
+
+	// Fail readiness and keep serving until the orchestrator stops routing requests here
+	log.Print("Shutting down")
+	h.StartDraining()
+	time.Sleep(cfg.GetDuration("SERVER_SHUTDOWN_DELAY"))
+
+	log.Print("Draining connections")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.GetDuration("SERVER_SHUTDOWN_TIMEOUT"))
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Print("Failed to drain connections: ", err)
+	}
+	if err := <-serverErr; !errors.Is(err, http.ErrServerClosed) {
+		log.Print("Server failed: ", err)
+	}
+
+	// Close the database once nothing uses it anymore
+	<-purger
+	if err := database.Close(); err != nil {
+		log.Print("Failed to close the database: ", err)
+	}
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			log.Print("Failed to close the rate limiting store: ", err)
+		}
+	}
+	// Draining may have used up its grace period, spans get their own
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), cfg.GetDuration("TRACING_SHUTDOWN_TIMEOUT"))
+	defer cancelFlush()
+	if err := tp.Shutdown(flushCtx); err != nil {
+		log.Print("Failed to flush the spans: ", err)
+	}
 }
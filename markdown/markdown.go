@@ -0,0 +1,137 @@
+// Package markdown renders markdown texts to sanitized HTML
+package markdown
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	// DefaultTaskURL is the link of task references, {id} is replaced by the id of the task
+	DefaultTaskURL = "/task/{id}"
+	// DefaultMentionURL is the link of mentions, {user} is replaced by the mentioned user
+	DefaultMentionURL = "/users/{user}"
+
+	// Classes of the links of task references and mentions
+	taskRefClass = "task-ref"
+	mentionClass = "mention"
+)
+
+// Renderer renders markdown to HTML safe to embed in a page.
+// #123 task references and @user mentions are turned into links.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+// NewRenderer returns a new Renderer linking task references to taskURL
+// and mentions to mentionURL
+func NewRenderer(taskURL, mentionURL string) *Renderer {
+	refs := &referenceTransformer{taskURL: taskURL, mentionURL: mentionURL}
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(refs, 500)),
+		),
+	)
+
+	// Without html.WithUnsafe goldmark omits raw HTML, sanitize anyway
+	// in case a markdown construct such as a link produces unsafe markup
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile("^(" + taskRefClass + "|" + mentionClass + ")$")).OnElements("a")
+	// Checkboxes of task lists
+	policy.AllowAttrs("type").Matching(regexp.MustCompile("^checkbox$")).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return &Renderer{md: md, policy: policy}
+}
+
+// Render returns the sanitized HTML rendering of the markdown source
+func (r *Renderer) Render(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+	return r.policy.Sanitize(buf.String()), nil
+}
+
+// referencePattern matches #123 task references and @user mentions not glued to a word,
+// like in emails. Dots and dashes ending a mention are left out as they end sentences.
+var referencePattern = regexp.MustCompile(`(^|[^\w])(#[0-9]+|@[\w.-]*\w)`)
+
+// referenceTransformer turns the task references and mentions found in texts into links.
+// Texts of links and code are left untouched.
+type referenceTransformer struct {
+	taskURL    string
+	mentionURL string
+}
+
+func (t *referenceTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+
+	var texts []*ast.Text
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link, *ast.AutoLink, *ast.CodeSpan, *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			texts = append(texts, n)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, n := range texts {
+		t.link(n, source)
+	}
+}
+
+// link splits the text node around its references
+func (t *referenceTransformer) link(n *ast.Text, source []byte) {
+	segment := n.Segment
+	value := segment.Value(source)
+	matches := referencePattern.FindAllSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return
+	}
+
+	parent := n.Parent()
+	start := 0
+	for _, m := range matches {
+		// m[4]:m[5] is the reference without the character before it
+		if m[4] > start {
+			parent.InsertBefore(parent, n, ast.NewTextSegment(text.NewSegment(segment.Start+start, segment.Start+m[4])))
+		}
+
+		ref := string(value[m[4]+1 : m[5]])
+		link := ast.NewLink()
+		if value[m[4]] == '#' {
+			link.Destination = []byte(strings.ReplaceAll(t.taskURL, "{id}", ref))
+			link.SetAttributeString("class", []byte(taskRefClass))
+		} else {
+			link.Destination = []byte(strings.ReplaceAll(t.mentionURL, "{user}", url.PathEscape(ref)))
+			link.SetAttributeString("class", []byte(mentionClass))
+		}
+		link.AppendChild(link, ast.NewTextSegment(text.NewSegment(segment.Start+m[4], segment.Start+m[5])))
+		parent.InsertBefore(parent, n, link)
+		start = m[5]
+	}
+
+	// Keep the rest of the text, with its line break if any
+	n.Segment = text.NewSegment(segment.Start+start, segment.Stop)
+	if n.Segment.Len() == 0 && !n.SoftLineBreak() && !n.HardLineBreak() {
+		parent.RemoveChild(parent, n)
+	}
+}
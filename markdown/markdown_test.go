@@ -0,0 +1,57 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	r := NewRenderer(DefaultTaskURL, DefaultMentionURL)
+	tests := []struct {
+		name string
+		src  string
+		html string
+	}{
+		{
+			name: "Markdown",
+			src:  "**bold** and [link](http://example.com)",
+			html: `<p><strong>bold</strong> and <a href="http://example.com" rel="nofollow">link</a></p>` + "\n",
+		},
+		{
+			name: "References and mentions",
+			src:  "Fixed by #123, thanks @john.doe.",
+			html: `<p>Fixed by <a href="/task/123" class="task-ref" rel="nofollow">#123</a>, thanks <a href="/users/john.doe" class="mention" rel="nofollow">@john.doe</a>.</p>` + "\n",
+		},
+		{
+			name: "References in code and links are left untouched",
+			src:  "`#12` [#5](http://example.com) issue#5 john@example.com",
+			html: `<p><code>#12</code> <a href="http://example.com" rel="nofollow">#5</a> issue#5 <a href="mailto:john@example.com" rel="nofollow">john@example.com</a></p>` + "\n",
+		},
+		{
+			name: "Task lists",
+			src:  "- [x] done",
+			html: "<ul>\n" + `<li><input checked="" disabled="" type="checkbox"> done</li>` + "\n</ul>\n",
+		},
+		{
+			name: "Scripts are removed",
+			src:  "Hi <script>alert(1)</script><img src=x onerror=alert(1)> [x](javascript:alert(1))",
+			html: "<p>Hi alert(1) x</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := r.Render(tt.src)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.html, html)
+		})
+	}
+}
+
+func TestRenderURLs(t *testing.T) {
+	r := NewRenderer("https://tasks.example.com/#/task/{id}", "https://example.com/people/{user}")
+	html, err := r.Render("#7 @jane")
+	assert.NoError(t, err)
+	assert.Equal(t, `<p><a href="https://tasks.example.com/#/task/7" class="task-ref" rel="nofollow">#7</a> <a href="https://example.com/people/jane" class="mention" rel="nofollow">@jane</a></p>`+"\n", html)
+}
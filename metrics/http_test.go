@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewHTTPMetrics(reg)
+
+	r := mux.NewRouter()
+	r.Use(m.Middleware)
+	r.HandleFunc("/task/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] == "99" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, 1.0, testutil.ToFloat64(m.inFlight.WithLabelValues("/task/{id:[0-9]+}")))
+		w.Write([]byte("{}"))
+	}).Methods("GET")
+
+	for _, path := range []string{"/task/1", "/task/2", "/task/99"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.requests.WithLabelValues("/task/{id:[0-9]+}", "GET", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("/task/{id:[0-9]+}", "GET", "404")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.inFlight.WithLabelValues("/task/{id:[0-9]+}")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.duration))
+}
+
+func TestHandler(t *testing.T) {
+	reg := NewRegistry()
+	NewHTTPMetrics(reg)
+
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	res := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.True(t, strings.Contains(res.Body.String(), "go_goroutines"))
+}
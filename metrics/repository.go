@@ -0,0 +1,202 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"todo-go/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TaskRepository wraps a models.TaskRepository to measure the latency and the
+// errors of its operations. It also reports the number of tasks by status when scraped.
+type TaskRepository struct {
+	repo     models.TaskRepository
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	tasks    *prometheus.Desc
+}
+
+// NewTaskRepository wraps repo and registers its metrics in reg
+func NewTaskRepository(repo models.TaskRepository, reg prometheus.Registerer) *TaskRepository {
+	r := &TaskRepository{
+		repo: repo,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of the operations of the task repository.",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "errors_total",
+			Help:      "Number of failed operations of the task repository.",
+		}, []string{"operation"}),
+		tasks: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "tasks"),
+			"Number of tasks by status, tasks in the trash excluded.",
+			[]string{"status"}, nil,
+		),
+	}
+	reg.MustRegister(r.duration, r.errors, r)
+	return r
+}
+
+// Describe implements prometheus.Collector
+func (r *TaskRepository) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.tasks
+}
+
+// Collect implements prometheus.Collector, it counts the tasks by status
+func (r *TaskRepository) Collect(ch chan<- prometheus.Metric) {
+	counts := make(map[models.Status]int)
+	for _, t := range r.repo.GetAllTasks() {
+		counts[t.Status]++
+	}
+	for s, n := range counts {
+		ch <- prometheus.MustNewConstMetric(r.tasks, prometheus.GaugeValue, float64(n), string(s))
+	}
+}
+
+// observe records the latency of the operation started at start, and its error if any
+func (r *TaskRepository) observe(op string, start time.Time, err error) {
+	r.duration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	if err != nil {
+		r.errors.WithLabelValues(op).Inc()
+	}
+}
+
+func (r *TaskRepository) GetTaskByID(id uint64) (*models.Task, error) {
+	start := time.Now()
+	v, err := r.repo.GetTaskByID(id)
+	r.observe("get_task_by_id", start, err)
+	return v, err
+}
+
+func (r *TaskRepository) GetAllTasks() []models.Task {
+	start := time.Now()
+	v := r.repo.GetAllTasks()
+	r.observe("get_all_tasks", start, nil)
+	return v
+}
+
+func (r *TaskRepository) FindTasks(f models.TaskFilter) []models.Task {
+	start := time.Now()
+	v := r.repo.FindTasks(f)
+	r.observe("find_tasks", start, nil)
+	return v
+}
+
+func (r *TaskRepository) CreateTask(t models.Task, c models.TaskCondition) (uint64, error) {
+	start := time.Now()
+	v, err := r.repo.CreateTask(t, c)
+	r.observe("create_task", start, err)
+	return v, err
+}
+
+func (r *TaskRepository) UpdateTask(t models.Task, c models.TaskCondition) error {
+	start := time.Now()
+	err := r.repo.UpdateTask(t, c)
+	r.observe("update_task", start, err)
+	return err
+}
+
+func (r *TaskRepository) DeleteTask(id uint64) error {
+	start := time.Now()
+	err := r.repo.DeleteTask(id)
+	r.observe("delete_task", start, err)
+	return err
+}
+
+func (r *TaskRepository) GetDeletedTasks() []models.Task {
+	start := time.Now()
+	v := r.repo.GetDeletedTasks()
+	r.observe("get_deleted_tasks", start, nil)
+	return v
+}
+
+func (r *TaskRepository) RestoreTask(id uint64) error {
+	start := time.Now()
+	err := r.repo.RestoreTask(id)
+	r.observe("restore_task", start, err)
+	return err
+}
+
+func (r *TaskRepository) PurgeTask(id uint64) (*models.Task, error) {
+	start := time.Now()
+	v, err := r.repo.PurgeTask(id)
+	r.observe("purge_task", start, err)
+	return v, err
+}
+
+func (r *TaskRepository) PurgeDeletedTasks(before time.Time) ([]models.Task, error) {
+	start := time.Now()
+	v, err := r.repo.PurgeDeletedTasks(before)
+	r.observe("purge_deleted_tasks", start, err)
+	return v, err
+}
+
+func (r *TaskRepository) AddAttachment(taskId uint64, a models.Attachment) (uint64, error) {
+	start := time.Now()
+	v, err := r.repo.AddAttachment(taskId, a)
+	r.observe("add_attachment", start, err)
+	return v, err
+}
+
+func (r *TaskRepository) DeleteAttachment(taskId uint64, attachmentId uint64) error {
+	start := time.Now()
+	err := r.repo.DeleteAttachment(taskId, attachmentId)
+	r.observe("delete_attachment", start, err)
+	return err
+}
+
+func (r *TaskRepository) MoveTask(id uint64, m models.TaskMove) (*models.Task, error) {
+	start := time.Now()
+	v, err := r.repo.MoveTask(id, m)
+	r.observe("move_task", start, err)
+	return v, err
+}
+
+func (r *TaskRepository) RebalancePositions() error {
+	start := time.Now()
+	err := r.repo.RebalancePositions()
+	r.observe("rebalance_positions", start, err)
+	return err
+}
+
+func (r *TaskRepository) AddChecklistItem(taskId uint64, i models.ChecklistItem) (uint64, error) {
+	start := time.Now()
+	v, err := r.repo.AddChecklistItem(taskId, i)
+	r.observe("add_checklist_item", start, err)
+	return v, err
+}
+
+func (r *TaskRepository) ToggleChecklistItem(taskId uint64, itemId uint64) error {
+	start := time.Now()
+	err := r.repo.ToggleChecklistItem(taskId, itemId)
+	r.observe("toggle_checklist_item", start, err)
+	return err
+}
+
+func (r *TaskRepository) MoveChecklistItem(taskId uint64, itemId uint64, index int) error {
+	start := time.Now()
+	err := r.repo.MoveChecklistItem(taskId, itemId, index)
+	r.observe("move_checklist_item", start, err)
+	return err
+}
+
+func (r *TaskRepository) DeleteChecklistItem(taskId uint64, itemId uint64) error {
+	start := time.Now()
+	err := r.repo.DeleteChecklistItem(taskId, itemId)
+	r.observe("delete_checklist_item", start, err)
+	return err
+}
+
+func (r *TaskRepository) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+	err := r.repo.HealthCheck(ctx)
+	r.observe("health_check", start, err)
+	return err
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskRepository(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	repo := NewTaskRepository(databases.NewInMemoryDatabase(), reg)
+
+	repo.CreateTask(models.Task{Title: "first", Status: models.StatusToDo}, models.TaskCondition{})
+	repo.CreateTask(models.Task{Title: "second", Status: models.StatusToDo}, models.TaskCondition{})
+	repo.CreateTask(models.Task{Title: "third", Status: models.StatusDone}, models.TaskCondition{})
+	repo.DeleteTask(1)
+
+	_, err := repo.GetTaskByID(0)
+	assert.NoError(t, err)
+	_, err = repo.GetTaskByID(99)
+	assert.Error(t, err)
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(repo.errors.WithLabelValues("create_task")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(repo.errors.WithLabelValues("get_task_by_id")))
+	assert.Equal(t, 3, testutil.CollectAndCount(repo.duration))
+
+	err = testutil.CollectAndCompare(repo, strings.NewReader(`
+# HELP todo_tasks Number of tasks by status, tasks in the trash excluded.
+# TYPE todo_tasks gauge
+todo_tasks{status="DONE"} 1
+todo_tasks{status="TODO"} 1
+`), "todo_tasks")
+	assert.NoError(t, err)
+}
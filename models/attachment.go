@@ -0,0 +1,23 @@
+package models
+
+import (
+	"io"
+	"time"
+)
+
+// Attachment is the metadata of a file attached to a task.
+// The content of the file is kept in a BlobStore under Key.
+type Attachment struct {
+	Id          uint64    `json:"id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Key         string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type BlobStore interface {
+	Put(key string, r io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
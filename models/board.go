@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrWIPLimitReached is returned when moving a task to a status
+// already holding as many tasks as its work in progress limit
+var ErrWIPLimitReached = errors.New("work in progress limit reached")
+
+// BoardColumn holds the tasks having a status, ordered by position
+type BoardColumn struct {
+	Status   Status `json:"status"`
+	WIPLimit int    `json:"wip_limit,omitempty"`
+	Count    int    `json:"count"`
+	Tasks    []Task `json:"tasks"`
+}
+
+// Board is a kanban board with a column per status
+type Board struct {
+	Columns []BoardColumn `json:"columns"`
+}
+
+// NewBoard dispatches the tasks in the columns of the statuses of the workflow,
+// in the order of the tasks. Tasks whose status isn't part of the workflow
+// are put in extra columns at the end of the board.
+func NewBoard(wf *Workflow, wipLimits map[Status]int, tasks []Task) *Board {
+	b := &Board{Columns: make([]BoardColumn, 0, len(wf.Statuses))}
+	columns := make(map[Status]int)
+	column := func(s Status) *BoardColumn {
+		k, ok := columns[s]
+		if !ok {
+			k = len(b.Columns)
+			columns[s] = k
+			b.Columns = append(b.Columns, BoardColumn{Status: s, WIPLimit: wipLimits[s], Tasks: make([]Task, 0)})
+		}
+		return &b.Columns[k]
+	}
+
+	for _, s := range wf.Statuses {
+		column(s)
+	}
+	for _, t := range tasks {
+		c := column(t.Status)
+		c.Tasks = append(c.Tasks, t)
+		c.Count++
+	}
+
+	return b
+}
+
+// ParseWIPLimits parses a comma separated list of work in progress limits
+// by status, eg: "INPROGRESS:5,BLOCKED:3". A limit of 0 means no limit.
+func ParseWIPLimits(s string) (map[Status]int, error) {
+	limits := make(map[Status]int)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		parts := strings.Split(v, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid work in progress limit %q: must be of the form STATUS:LIMIT", v)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid work in progress limit %q: limit must be 0 or a positive number", v)
+		}
+		limits[Status(strings.TrimSpace(parts[0]))] = limit
+	}
+	return limits, nil
+}
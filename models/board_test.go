@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBoard(t *testing.T) {
+	wf, _ := ParseWorkflow("TODO,INPROGRESS,DONE", "TODO->INPROGRESS,INPROGRESS->DONE")
+	tasks := []Task{
+		{Id: 0, Status: StatusInProgress},
+		{Id: 1, Status: StatusToDo},
+		{Id: 2, Status: StatusInProgress},
+		{Id: 3, Status: "LEGACY"},
+	}
+
+	b := NewBoard(wf, map[Status]int{StatusInProgress: 2}, tasks)
+	assert.Len(t, b.Columns, 4)
+
+	assert.Equal(t, Status(StatusToDo), b.Columns[0].Status)
+	assert.Equal(t, 1, b.Columns[0].Count)
+	assert.Equal(t, 0, b.Columns[0].WIPLimit)
+
+	assert.Equal(t, Status(StatusInProgress), b.Columns[1].Status)
+	assert.Equal(t, 2, b.Columns[1].Count)
+	assert.Equal(t, 2, b.Columns[1].WIPLimit)
+	assert.Equal(t, uint64(0), b.Columns[1].Tasks[0].Id)
+	assert.Equal(t, uint64(2), b.Columns[1].Tasks[1].Id)
+
+	assert.Equal(t, Status(StatusDone), b.Columns[2].Status)
+	assert.Equal(t, 0, b.Columns[2].Count)
+	assert.NotNil(t, b.Columns[2].Tasks)
+
+	assert.Equal(t, Status("LEGACY"), b.Columns[3].Status)
+	assert.Equal(t, 1, b.Columns[3].Count)
+}
+
+func TestParseWIPLimits(t *testing.T) {
+	t.Run("Parse limits", func(t *testing.T) {
+		limits, err := ParseWIPLimits(" INPROGRESS:5, BLOCKED : 3 ")
+		assert.NoError(t, err)
+		assert.Equal(t, map[Status]int{StatusInProgress: 5, StatusBlocked: 3}, limits)
+
+		limits, err = ParseWIPLimits("")
+		assert.NoError(t, err)
+		assert.Empty(t, limits)
+
+		// 0 disables the limit of the status
+		limits, err = ParseWIPLimits("INPROGRESS:0")
+		assert.NoError(t, err)
+		assert.Equal(t, map[Status]int{StatusInProgress: 0}, limits)
+	})
+
+	t.Run("Parse invalid limits", func(t *testing.T) {
+		_, err := ParseWIPLimits("INPROGRESS")
+		assert.Error(t, err)
+		_, err = ParseWIPLimits("INPROGRESS:many")
+		assert.Error(t, err)
+		_, err = ParseWIPLimits("INPROGRESS:-1")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,31 @@
+package models
+
+// ChecklistItem is an entry of the checklist of a task
+type ChecklistItem struct {
+	Id      uint64 `json:"id"`
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+// ChecklistCompletion returns the percentage of checked items, rounded down.
+// It returns nil for an empty checklist.
+func ChecklistCompletion(items []ChecklistItem) *int {
+	if len(items) == 0 {
+		return nil
+	}
+
+	checked := 0
+	for _, i := range items {
+		if i.Checked {
+			checked++
+		}
+	}
+	completion := checked * 100 / len(items)
+	return &completion
+}
+
+// ChecklistDone returns whether the checklist has items and all of them are checked
+func ChecklistDone(items []ChecklistItem) bool {
+	c := ChecklistCompletion(items)
+	return c != nil && *c == 100
+}
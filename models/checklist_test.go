@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklistCompletion(t *testing.T) {
+	assert.Nil(t, ChecklistCompletion(nil))
+	assert.False(t, ChecklistDone(nil))
+
+	items := []ChecklistItem{{Checked: true}, {}, {}}
+	assert.Equal(t, 33, *ChecklistCompletion(items))
+	assert.False(t, ChecklistDone(items))
+
+	items[1].Checked = true
+	items[2].Checked = true
+	assert.Equal(t, 100, *ChecklistCompletion(items))
+	assert.True(t, ChecklistDone(items))
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Comment is a markdown message posted on a task
+type Comment struct {
+	Id        uint64    `json:"id"`
+	TaskId    uint64    `json:"task_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CommentRepository interface {
+	GetTaskComments(taskId uint64) []Comment
+	GetCommentByID(id uint64) (*Comment, error)
+	CreateComment(c Comment) (uint64, error)
+	UpdateComment(c Comment) error
+	DeleteComment(id uint64) error
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldString CustomFieldType = "string"
+	CustomFieldNumber CustomFieldType = "number"
+	CustomFieldEnum   CustomFieldType = "enum"
+	CustomFieldDate   CustomFieldType = "date"
+	CustomFieldBool   CustomFieldType = "bool"
+
+	// Layout of the values of date custom fields
+	CustomFieldDateLayout = "2006-01-02"
+)
+
+// ErrCustomFieldsInUse is returned when changing the custom fields of a project
+// in a way the values its tasks already hold wouldn't be valid anymore
+var ErrCustomFieldsInUse = errors.New("custom fields are in use by tasks of the project")
+
+// CustomField is the definition of a typed field a project adds to its tasks
+type CustomField struct {
+	Name     string          `json:"name"`
+	Type     CustomFieldType `json:"type"`
+	Options  []string        `json:"options,omitempty"` // Allowed values of enum fields
+	Required bool            `json:"required"`
+}
+
+// ValidateCustomFieldsSchema checks the definitions of custom fields are valid
+func ValidateCustomFieldsSchema(schema []CustomField) error {
+	names := make(map[string]bool)
+	for _, f := range schema {
+		if strings.TrimSpace(f.Name) == "" {
+			return fmt.Errorf("custom field name must not be empty")
+		}
+		if names[f.Name] {
+			return fmt.Errorf("custom field %s is declared twice", f.Name)
+		}
+		names[f.Name] = true
+
+		switch f.Type {
+		case CustomFieldString, CustomFieldNumber, CustomFieldDate, CustomFieldBool:
+		case CustomFieldEnum:
+			if len(f.Options) == 0 {
+				return fmt.Errorf("enum custom field %s must declare options", f.Name)
+			}
+		default:
+			return fmt.Errorf("custom field %s has unknown type %s", f.Name, f.Type)
+		}
+	}
+	return nil
+}
+
+// ValidateCustomFields checks the values of custom fields against their schema.
+// It returns the values normalized: numbers as float64, booleans as bool,
+// strings, enums and dates as string.
+func ValidateCustomFields(schema []CustomField, values map[string]interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]CustomField, len(schema))
+	for _, f := range schema {
+		fields[f.Name] = f
+	}
+
+	normalized := make(map[string]interface{}, len(values))
+	for name, v := range values {
+		f, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field %s", name)
+		}
+		// A null value unsets the field
+		if v == nil {
+			continue
+		}
+		n, err := f.Normalize(v)
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = n
+	}
+
+	for _, f := range schema {
+		if _, ok := normalized[f.Name]; f.Required && !ok {
+			return nil, fmt.Errorf("custom field %s is required", f.Name)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// Normalize checks the value has the type of the field and returns its normalized form
+func (f CustomField) Normalize(v interface{}) (interface{}, error) {
+	switch f.Type {
+	case CustomFieldString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case CustomFieldNumber:
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		}
+	case CustomFieldBool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case CustomFieldEnum:
+		if s, ok := v.(string); ok {
+			for _, o := range f.Options {
+				if s == o {
+					return s, nil
+				}
+			}
+			return nil, fmt.Errorf("custom field %s must be one of %s", f.Name, strings.Join(f.Options, ", "))
+		}
+	case CustomFieldDate:
+		if s, ok := v.(string); ok {
+			if _, err := time.Parse(CustomFieldDateLayout, s); err == nil {
+				return s, nil
+			}
+			return nil, fmt.Errorf("custom field %s must be a date formatted as %s", f.Name, CustomFieldDateLayout)
+		}
+	}
+
+	return nil, fmt.Errorf("custom field %s must be of type %s", f.Name, f.Type)
+}
+
+// FormatCustomValue returns the textual representation of a normalized custom field value,
+// as used to filter tasks in query strings
+func FormatCustomValue(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return n
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(n)
+	default:
+		return fmt.Sprint(n)
+	}
+}
+
+// CompareCustomValues compares two normalized custom field values.
+// It returns -1, 0 or +1. Missing values are greater than any other value
+// so they come last in ascending order.
+func CompareCustomValues(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		default:
+			return -1
+		}
+	}
+
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// Strings, enums, dates and values of mismatching types.
+	// Dates are ISO formatted so they sort as strings.
+	return strings.Compare(FormatCustomValue(a), FormatCustomValue(b))
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var customFieldsSchema = []CustomField{
+	{Name: "points", Type: CustomFieldNumber, Required: true},
+	{Name: "customer", Type: CustomFieldString},
+	{Name: "size", Type: CustomFieldEnum, Options: []string{"S", "M", "L"}},
+	{Name: "sprint_end", Type: CustomFieldDate},
+	{Name: "billable", Type: CustomFieldBool},
+}
+
+func TestValidateCustomFieldsSchema(t *testing.T) {
+	t.Run("Valid schema", func(t *testing.T) {
+		assert.NoError(t, ValidateCustomFieldsSchema(customFieldsSchema))
+	})
+
+	t.Run("Invalid schemas", func(t *testing.T) {
+		assert.Error(t, ValidateCustomFieldsSchema([]CustomField{{Name: "", Type: CustomFieldString}}))
+		assert.Error(t, ValidateCustomFieldsSchema([]CustomField{{Name: "a", Type: CustomFieldString}, {Name: "a", Type: CustomFieldBool}}))
+		assert.Error(t, ValidateCustomFieldsSchema([]CustomField{{Name: "a", Type: "color"}}))
+		assert.Error(t, ValidateCustomFieldsSchema([]CustomField{{Name: "a", Type: CustomFieldEnum}}))
+	})
+}
+
+func TestValidateCustomFields(t *testing.T) {
+	t.Run("Valid values", func(t *testing.T) {
+		values, err := ValidateCustomFields(customFieldsSchema, map[string]interface{}{
+			"points":     3,
+			"customer":   "acme",
+			"size":       "M",
+			"sprint_end": "2026-11-01",
+			"billable":   true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, float64(3), values["points"])
+		assert.Equal(t, "M", values["size"])
+	})
+
+	t.Run("Null values are removed", func(t *testing.T) {
+		values, err := ValidateCustomFields(customFieldsSchema, map[string]interface{}{"points": 1.5, "customer": nil})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"points": 1.5}, values)
+	})
+
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+	}{
+		{"Missing required field", map[string]interface{}{"customer": "acme"}},
+		{"Unknown field", map[string]interface{}{"points": 1, "color": "red"}},
+		{"Wrong number", map[string]interface{}{"points": "1"}},
+		{"Wrong string", map[string]interface{}{"points": 1, "customer": 42.0}},
+		{"Wrong enum", map[string]interface{}{"points": 1, "size": "XL"}},
+		{"Wrong date", map[string]interface{}{"points": 1, "sprint_end": "01/11/2026"}},
+		{"Wrong bool", map[string]interface{}{"points": 1, "billable": "yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateCustomFields(customFieldsSchema, tt.values)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCompareCustomValues(t *testing.T) {
+	assert.Equal(t, -1, CompareCustomValues(2.0, 10.0))
+	assert.Equal(t, 1, CompareCustomValues("b", "a"))
+	assert.Equal(t, 0, CompareCustomValues(true, true))
+	assert.Equal(t, -1, CompareCustomValues(false, true))
+	assert.Equal(t, -1, CompareCustomValues("2026-01-01", "2026-11-01"))
+	assert.Equal(t, 1, CompareCustomValues(nil, 1.0))
+	assert.Equal(t, -1, CompareCustomValues(1.0, nil))
+	assert.Equal(t, 0, CompareCustomValues(nil, nil))
+}
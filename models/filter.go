@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"todo-go/query"
+)
+
+// Prefix of the custom fields when filtering or sorting tasks
+const CustomFieldPrefix = "cf."
+
+// Task fields tasks can be sorted on, besides custom fields
+var sortableTaskFields = map[string]bool{
+	"id":         true,
+	"title":      true,
+	"priority":   true,
+	"status":     true,
+	"position":   true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// TaskFilter selects tasks and the order they are returned in.
+// Zero values match every task.
+type TaskFilter struct {
+	// Tasks having one of the statuses
+	Statuses []Status
+	// Tasks having the priority
+	Priority *Priority
+	// Tasks belonging to the project
+	ProjectId *uint64
+	// Include the tasks of archived projects
+	IncludeArchived bool
+	// Tasks whose custom fields have the given values, as formatted by FormatCustomValue
+	CustomFields map[string]string
+	// Tasks matching the filter expression, as returned by ParseTaskQuery
+	Query query.Expr
+	// Order of the tasks, by successive keys. Tasks are sorted by position by default.
+	Sort []SortKey
+}
+
+// SortKey is a field tasks are sorted on. Custom fields are prefixed by CustomFieldPrefix.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSortKeys parses a comma separated list of fields to sort tasks on.
+// Fields prefixed by "-" are sorted in descending order, eg: "-priority,cf.story_points"
+func ParseSortKeys(s string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+
+		k := SortKey{Field: v}
+		if strings.HasPrefix(v, "-") {
+			k = SortKey{Field: v[1:], Desc: true}
+		}
+		if !sortableTaskFields[k.Field] && (!strings.HasPrefix(k.Field, CustomFieldPrefix) || k.Field == CustomFieldPrefix) {
+			return nil, fmt.Errorf("tasks can't be sorted on %s", k.Field)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
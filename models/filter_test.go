@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	t.Run("Parse sort keys", func(t *testing.T) {
+		keys, err := ParseSortKeys("-priority, cf.points,title")
+		assert.NoError(t, err)
+		assert.Equal(t, []SortKey{
+			{Field: "priority", Desc: true},
+			{Field: "cf.points"},
+			{Field: "title"},
+		}, keys)
+	})
+
+	t.Run("Parse unknown sort key", func(t *testing.T) {
+		_, err := ParseSortKeys("body")
+		assert.Error(t, err)
+		_, err = ParseSortKeys("cf.")
+		assert.Error(t, err)
+	})
+}
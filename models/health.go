@@ -0,0 +1,31 @@
+package models
+
+type HealthStatus string
+
+const (
+	HealthStatusOK          HealthStatus = "ok"
+	HealthStatusUnavailable HealthStatus = "unavailable"
+)
+
+// ComponentHealth is the health of a component the service depends on
+type ComponentHealth struct {
+	Status HealthStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// HealthReport is the health of the service, unavailable if any of its components is
+type HealthReport struct {
+	Status     HealthStatus               `json:"status"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
+
+// NewHealthReport returns the report of the health of the given components
+func NewHealthReport(components map[string]ComponentHealth) *HealthReport {
+	r := &HealthReport{Status: HealthStatusOK, Components: components}
+	for _, c := range components {
+		if c.Status != HealthStatusOK {
+			r.Status = HealthStatusUnavailable
+		}
+	}
+	return r
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+type HistoryAction string
+
+const (
+	HistoryActionCreate  HistoryAction = "create"
+	HistoryActionUpdate  HistoryAction = "update"
+	HistoryActionDelete  HistoryAction = "delete"
+	HistoryActionRestore HistoryAction = "restore"
+	HistoryActionPurge   HistoryAction = "purge"
+)
+
+// FieldChange is the change of a single task field
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// HistoryEntry records a change made to a task
+type HistoryEntry struct {
+	Id        uint64        `json:"id"`
+	TaskId    uint64        `json:"task_id"`
+	Action    HistoryAction `json:"action"`
+	Changes   []FieldChange `json:"changes"`
+	Actor     string        `json:"actor"`
+	RequestId string        `json:"request_id,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+type HistoryRepository interface {
+	AddHistoryEntry(e HistoryEntry) (uint64, error)
+	GetTaskHistory(taskId uint64) []HistoryEntry
+}
+
+// Fields managed by the repositories are not part of the diffs
+var untrackedTaskFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	// Derived from the checklist, whose changes are already recorded
+	"checklist_completion": true,
+}
+
+// DiffTasks returns the list of fields that differ between old and new.
+// Fields are named after their json representation. A nil task is
+// considered empty, so it can be used to diff a creation or a deletion.
+func DiffTasks(old, new *Task) []FieldChange {
+	if old == nil {
+		old = &Task{}
+	}
+	if new == nil {
+		new = &Task{}
+	}
+
+	changes := make([]FieldChange, 0)
+	o := reflect.ValueOf(*old)
+	n := reflect.ValueOf(*new)
+	for i := 0; i < o.NumField(); i++ {
+		name := strings.Split(o.Type().Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || untrackedTaskFields[name] {
+			continue
+		}
+
+		ov, nv := o.Field(i).Interface(), n.Field(i).Interface()
+		if !reflect.DeepEqual(ov, nv) {
+			changes = append(changes, FieldChange{Field: name, Old: ov, New: nv})
+		}
+	}
+
+	return changes
+}
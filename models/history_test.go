@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTasks(t *testing.T) {
+	t.Run("Diff identical tasks", func(t *testing.T) {
+		task := &Task{Id: 1, Title: "title", Priority: High}
+		assert.Empty(t, DiffTasks(task, task))
+	})
+
+	t.Run("Diff updated task", func(t *testing.T) {
+		old := &Task{Id: 1, Title: "title", Priority: High, Status: StatusToDo, UpdatedAt: time.Now()}
+		new := &Task{Id: 1, Title: "title", Priority: Low, Status: StatusInProgress}
+
+		changes := DiffTasks(old, new)
+		assert.Equal(t, []FieldChange{
+			{Field: "priority", Old: High, New: Low},
+			{Field: "status", Old: Status(StatusToDo), New: Status(StatusInProgress)},
+		}, changes)
+	})
+
+	t.Run("Diff created task", func(t *testing.T) {
+		changes := DiffTasks(nil, &Task{Title: "title"})
+		assert.Equal(t, []FieldChange{{Field: "title", Old: "", New: "title"}}, changes)
+	})
+
+	t.Run("Diff deleted task", func(t *testing.T) {
+		changes := DiffTasks(&Task{Body: "body"}, nil)
+		assert.Equal(t, []FieldChange{{Field: "body", Old: "body", New: ""}}, changes)
+	})
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultLinkTypesList is the list of link types available by default
+const DefaultLinkTypesList = "duplicates:duplicated_by,relates_to,caused_by:causes"
+
+// LinkType is a relation between two tasks. It reads Name from the source
+// of the link and Inverse from its target. Symmetric types have no inverse.
+type LinkType struct {
+	Name    string `json:"name"`
+	Inverse string `json:"inverse,omitempty"`
+}
+
+// Link is a typed relation from a source task to a target task
+type Link struct {
+	Id        uint64    `json:"id"`
+	SourceId  uint64    `json:"source_id"`
+	TargetId  uint64    `json:"target_id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TaskLink is a link as seen from one of its tasks
+type TaskLink struct {
+	Id     uint64 `json:"id"`
+	Type   string `json:"type"`
+	TaskId uint64 `json:"task_id"`
+}
+
+type LinkRepository interface {
+	GetTaskLinks(taskId uint64) []Link
+	GetLinkByID(id uint64) (*Link, error)
+	CreateLink(l Link) (uint64, error)
+	DeleteLink(id uint64) error
+}
+
+// LinkTypes is the list of link types tasks can be linked with
+type LinkTypes []LinkType
+
+// ParseLinkTypes parses a comma separated list of link types.
+// Each type is either NAME:INVERSE or NAME for symmetric types,
+// eg: "duplicates:duplicated_by,relates_to"
+func ParseLinkTypes(s string) (LinkTypes, error) {
+	var types LinkTypes
+	names := make(map[string]bool)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		parts := strings.Split(v, ":")
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("invalid link type %q: must be of the form NAME:INVERSE or NAME", v)
+		}
+
+		lt := LinkType{Name: strings.TrimSpace(parts[0])}
+		if len(parts) == 2 {
+			lt.Inverse = strings.TrimSpace(parts[1])
+		}
+		if lt.Name == "" || (len(parts) == 2 && lt.Inverse == "") {
+			return nil, fmt.Errorf("invalid link type %q: names must not be empty", v)
+		}
+		for _, n := range []string{lt.Name, lt.Inverse} {
+			if n == "" {
+				continue
+			}
+			if names[n] {
+				return nil, fmt.Errorf("link type %s is declared twice", n)
+			}
+			names[n] = true
+		}
+		types = append(types, lt)
+	}
+	return types, nil
+}
+
+// DefaultLinkTypes returns the default link types
+func DefaultLinkTypes() LinkTypes {
+	types, err := ParseLinkTypes(DefaultLinkTypesList)
+	if err != nil {
+		panic(err)
+	}
+	return types
+}
+
+// Resolve returns the link type named name, from either side of the link.
+// inverse is true if name is the inverse name of the type.
+func (types LinkTypes) Resolve(name string) (lt LinkType, inverse bool, err error) {
+	for _, t := range types {
+		if t.Name == name {
+			return t, false, nil
+		}
+		if t.Inverse != "" && t.Inverse == name {
+			return t, true, nil
+		}
+	}
+	return LinkType{}, false, fmt.Errorf("unknown link type %s", name)
+}
+
+// Symmetric returns whether the link type reads the same from both sides
+func (lt LinkType) Symmetric() bool {
+	return lt.Inverse == ""
+}
+
+// NewLink returns the link of the given type, named from the side of the source task.
+// Links of symmetric types always go from the lowest task id to the highest one
+// so a relation between two tasks has a single form.
+func (types LinkTypes) NewLink(sourceId, targetId uint64, name string) (Link, error) {
+	lt, inverse, err := types.Resolve(name)
+	if err != nil {
+		return Link{}, err
+	}
+	if sourceId == targetId {
+		return Link{}, fmt.Errorf("a task can't be linked to itself")
+	}
+	if inverse || (lt.Symmetric() && sourceId > targetId) {
+		sourceId, targetId = targetId, sourceId
+	}
+	return Link{SourceId: sourceId, TargetId: targetId, Type: lt.Name}, nil
+}
+
+// From returns the link as seen from the given task
+func (types LinkTypes) From(taskId uint64, l Link) TaskLink {
+	if l.SourceId == taskId {
+		return TaskLink{Id: l.Id, Type: l.Type, TaskId: l.TargetId}
+	}
+
+	tl := TaskLink{Id: l.Id, Type: l.Type, TaskId: l.SourceId}
+	if lt, _, err := types.Resolve(l.Type); err == nil && !lt.Symmetric() {
+		tl.Type = lt.Inverse
+	}
+	return tl
+}
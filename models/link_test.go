@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinkTypes(t *testing.T) {
+	t.Run("Parse link types", func(t *testing.T) {
+		types, err := ParseLinkTypes(" blocks : blocked_by , relates_to ")
+		assert.NoError(t, err)
+		assert.Equal(t, LinkTypes{{Name: "blocks", Inverse: "blocked_by"}, {Name: "relates_to"}}, types)
+		assert.False(t, types[0].Symmetric())
+		assert.True(t, types[1].Symmetric())
+	})
+
+	t.Run("Parse invalid link types", func(t *testing.T) {
+		for _, s := range []string{"a:b:c", ":b", "a:", "a:b,b", "a,a"} {
+			_, err := ParseLinkTypes(s)
+			assert.Error(t, err, s)
+		}
+	})
+
+	t.Run("Parse default link types", func(t *testing.T) {
+		assert.NotPanics(t, func() { DefaultLinkTypes() })
+	})
+}
+
+func TestNewLink(t *testing.T) {
+	types := DefaultLinkTypes()
+
+	l, err := types.NewLink(1, 2, "duplicates")
+	assert.NoError(t, err)
+	assert.Equal(t, Link{SourceId: 1, TargetId: 2, Type: "duplicates"}, l)
+	assert.Equal(t, TaskLink{Type: "duplicates", TaskId: 2}, types.From(1, l))
+	assert.Equal(t, TaskLink{Type: "duplicated_by", TaskId: 1}, types.From(2, l))
+
+	// Links named from the target side are stored from the source side
+	l, err = types.NewLink(1, 2, "causes")
+	assert.NoError(t, err)
+	assert.Equal(t, Link{SourceId: 2, TargetId: 1, Type: "caused_by"}, l)
+	assert.Equal(t, TaskLink{Type: "causes", TaskId: 2}, types.From(1, l))
+
+	// Symmetric links have a single form
+	l, err = types.NewLink(2, 1, "relates_to")
+	assert.NoError(t, err)
+	assert.Equal(t, Link{SourceId: 1, TargetId: 2, Type: "relates_to"}, l)
+	assert.Equal(t, TaskLink{Type: "relates_to", TaskId: 1}, types.From(2, l))
+
+	_, err = types.NewLink(1, 1, "relates_to")
+	assert.Error(t, err)
+	_, err = types.NewLink(1, 2, "blocks")
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Project groups tasks. Tasks of an archived project are hidden
+// from the default listings.
+type Project struct {
+	Id          uint64 `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Archived    bool   `json:"archived"`
+	// Definitions of the custom fields of the tasks of the project
+	CustomFields []CustomField `json:"custom_fields,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type ProjectRepository interface {
+	GetProjectByID(id uint64) (*Project, error)
+	GetAllProjects() []Project
+	CreateProject(p Project) (uint64, error)
+	UpdateProject(p Project) error
+	DeleteProject(id uint64) error
+}
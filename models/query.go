@@ -0,0 +1,149 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo-go/query"
+)
+
+// Layout of the dates of task filters
+const QueryDateLayout = "2006-01-02"
+
+// Kinds of the task fields filters compare
+type queryFieldKind int
+
+const (
+	queryText queryFieldKind = iota
+	queryStatus
+	queryPriority
+	queryProject
+	queryNumber
+	queryDate
+	queryCustomField
+)
+
+// Task fields filters can compare, custom fields are prefixed by CustomFieldPrefix.
+// text and bare words search the title and the body.
+var queryFields = map[string]queryFieldKind{
+	"":                 queryText,
+	"text":             queryText,
+	"title":            queryText,
+	"body":             queryText,
+	"status":           queryStatus,
+	"priority":         queryPriority,
+	"project_id":       queryProject,
+	"estimate_minutes": queryNumber,
+	"created_at":       queryDate,
+	"updated_at":       queryDate,
+}
+
+var priorityNames = []string{"Lowest", "Low", "Medium", "High", "Highest"}
+
+// ParsePriority parses a priority from its number or its name, eg: "3" or "high"
+func ParsePriority(s string) (Priority, error) {
+	if n, err := strconv.Atoi(s); err == nil && n >= int(Lowest) && n <= int(Highest) {
+		return Priority(n), nil
+	}
+	for k, name := range priorityNames {
+		if strings.EqualFold(s, name) {
+			return Priority(k), nil
+		}
+	}
+	return 0, fmt.Errorf("priority must be a number from %d to %d or one of %s", Lowest, Highest, strings.Join(priorityNames, ", "))
+}
+
+// ParseTaskQuery parses a task filter expression, see package query for its syntax.
+// The comparisons of the expression are checked against the fields of tasks and
+// their values resolved to:
+//   - title, body, text and bare words: lowercase string, ":" matches substrings.
+//     text and bare words search the title and the body.
+//   - status: Status
+//   - priority: Priority, by number or name
+//   - project_id: *uint64, nil for "none"
+//   - estimate_minutes: int
+//   - created_at, updated_at: time.Time of the start of the day, in UTC.
+//     Dates compare to whole days, eg: created_at<=2026-01-31 includes the 31st.
+//   - cf.<name>: string, converted to the type of the custom field of each task
+//
+// ":" and "=" are the same for other fields than texts.
+//
+// Tasks have no tags nor due date, so there is no tag field nor due and overdue
+// filters: a bare overdue searches the text "overdue". Due dates kept in a date
+// custom field are compared as cf.due<2026-11-01.
+func ParseTaskQuery(s string) (query.Expr, error) {
+	e, err := query.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.Walk(e, func(c *query.Comparison) error {
+		kind, ok := queryFields[c.Field]
+		if strings.HasPrefix(c.Field, CustomFieldPrefix) && len(c.Field) > len(CustomFieldPrefix) {
+			kind, ok = queryCustomField, true
+		}
+		if !ok {
+			return &query.Error{Pos: c.Pos, Msg: fmt.Sprintf("unknown field %s", c.Field)}
+		}
+
+		ordered := c.Op != query.OpMatch && c.Op != query.OpEqual && c.Op != query.OpNotEqual
+		switch kind {
+		case queryText:
+			if ordered {
+				return &query.Error{Pos: c.Pos, Msg: fmt.Sprintf("%s can't be compared with %s", c.Field, c.Op)}
+			}
+			if c.Field == "" {
+				c.Field = "text"
+			}
+			c.Resolved = strings.ToLower(c.Value)
+		case queryStatus:
+			if ordered {
+				return &query.Error{Pos: c.Pos, Msg: fmt.Sprintf("status can't be compared with %s", c.Op)}
+			}
+			c.Resolved = Status(c.Value)
+		case queryPriority:
+			p, err := ParsePriority(c.Value)
+			if err != nil {
+				return &query.Error{Pos: c.Pos, Msg: err.Error()}
+			}
+			c.Resolved = p
+		case queryProject:
+			if ordered {
+				return &query.Error{Pos: c.Pos, Msg: fmt.Sprintf("project_id can't be compared with %s", c.Op)}
+			}
+			if c.Value == "none" {
+				c.Resolved = (*uint64)(nil)
+				break
+			}
+			id, err := strconv.ParseUint(c.Value, 10, 64)
+			if err != nil {
+				return &query.Error{Pos: c.Pos, Msg: "project_id must be a number or none"}
+			}
+			c.Resolved = &id
+		case queryNumber:
+			n, err := strconv.Atoi(c.Value)
+			if err != nil {
+				return &query.Error{Pos: c.Pos, Msg: fmt.Sprintf("%s must be a number", c.Field)}
+			}
+			c.Resolved = n
+		case queryDate:
+			d, err := time.Parse(QueryDateLayout, c.Value)
+			if err != nil {
+				return &query.Error{Pos: c.Pos, Msg: fmt.Sprintf("%s must be a date formatted as %s", c.Field, QueryDateLayout)}
+			}
+			c.Resolved = d
+		case queryCustomField:
+			c.Resolved = c.Value
+		}
+		if c.Op == query.OpMatch && kind != queryText {
+			c.Op = query.OpEqual
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+	"time"
+	"todo-go/query"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePriority(t *testing.T) {
+	p, err := ParsePriority("high")
+	assert.NoError(t, err)
+	assert.Equal(t, High, p)
+
+	p, err = ParsePriority("1")
+	assert.NoError(t, err)
+	assert.Equal(t, Low, p)
+
+	_, err = ParsePriority("urgent")
+	assert.Error(t, err)
+	_, err = ParsePriority("9")
+	assert.Error(t, err)
+}
+
+func TestParseTaskQuery(t *testing.T) {
+	t.Run("Resolve values", func(t *testing.T) {
+		e, err := ParseTaskQuery(`status:TODO priority>=High project_id:none project_id:2 estimate_minutes<60 created_at<2026-11-01 cf.team:backend Login`)
+		assert.NoError(t, err)
+
+		var resolved []interface{}
+		query.Walk(e, func(c *query.Comparison) error {
+			resolved = append(resolved, c.Resolved)
+			return nil
+		})
+		project := uint64(2)
+		assert.Equal(t, []interface{}{
+			Status(StatusToDo),
+			High,
+			(*uint64)(nil),
+			&project,
+			60,
+			time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			"backend",
+			"login",
+		}, resolved)
+	})
+
+	t.Run("Parse the example of package query", func(t *testing.T) {
+		_, err := ParseTaskQuery(`status:TODO priority>=High (created_at<2026-11-01 OR NOT project_id:none)`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Normalize operators", func(t *testing.T) {
+		e, err := ParseTaskQuery(`status:TODO title:login`)
+		assert.NoError(t, err)
+		assert.Equal(t, `(status="TODO" AND title:"login")`, e.String())
+
+		e, err = ParseTaskQuery(`login`)
+		assert.NoError(t, err)
+		assert.Equal(t, `text:"login"`, e.String())
+	})
+
+	t.Run("Reject invalid comparisons", func(t *testing.T) {
+		tests := []struct {
+			expr string
+			err  string
+		}{
+			{`due<2026-11-01`, "position 1: unknown field due"},
+			{`tag:backend`, "position 1: unknown field tag"},
+			{`status:TODO priority:urgent`, "position 13: priority must be a number from 0 to 4 or one of Lowest, Low, Medium, High, Highest"},
+			{`status>TODO`, "position 1: status can't be compared with >"},
+			{`title>a`, "position 1: title can't be compared with >"},
+			{`project_id:inbox`, "position 1: project_id must be a number or none"},
+			{`estimate_minutes>1h`, "position 1: estimate_minutes must be a number"},
+			{`created_at<tomorrow`, "position 1: created_at must be a date formatted as 2006-01-02"},
+			{`cf.:x`, "position 1: unknown field cf."},
+			{`status:`, "position 8: expected a value after status:, got end of filter"},
+		}
+		for _, tt := range tests {
+			_, err := ParseTaskQuery(tt.expr)
+			assert.EqualError(t, err, tt.err, tt.expr)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Digits of the lexicographic ranks used to order tasks.
+// Ranks never end with the first digit so there is always room before them.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const rankBase = len(rankDigits)
+
+// rankDigit returns the value of the digit at position pos of rank.
+// Positions past the end of the rank are worth def.
+func rankDigit(rank string, pos, def int) int {
+	if pos >= len(rank) {
+		return def
+	}
+	return strings.IndexByte(rankDigits, rank[pos])
+}
+
+func validateRank(rank string) error {
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(rankDigits, rank[i]) < 0 {
+			return fmt.Errorf("invalid rank %q", rank)
+		}
+	}
+	if strings.HasSuffix(rank, rankDigits[:1]) {
+		return fmt.Errorf("invalid rank %q: must not end with %q", rank, rankDigits[0])
+	}
+	return nil
+}
+
+// RankBetween returns a rank sorting strictly between prev and next.
+// An empty prev is the lowest rank and an empty next the highest one,
+// so RankBetween("", "") returns the rank of the first task of a list.
+func RankBetween(prev, next string) (string, error) {
+	if err := validateRank(prev); err != nil {
+		return "", err
+	}
+	if err := validateRank(next); err != nil {
+		return "", err
+	}
+	if next != "" && prev >= next {
+		return "", fmt.Errorf("rank %q must be lower than rank %q", prev, next)
+	}
+
+	// Find the leftmost differing digit. Past their end, prev is
+	// worth -1 and next is worth rankBase.
+	var p, n, pos int
+	for {
+		p = rankDigit(prev, pos, -1)
+		n = rankDigit(next, pos, rankBase)
+		pos++
+		if p != n {
+			break
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(prev[:pos-1])
+	if p == -1 {
+		// prev is a prefix of next, match the lowest digits of next
+		for n == 0 {
+			b.WriteByte(rankDigits[0])
+			n = rankDigit(next, pos, rankBase)
+			pos++
+		}
+		if n == 1 {
+			b.WriteByte(rankDigits[0])
+			n = rankBase
+		}
+	} else if p+1 == n {
+		// Consecutive digits, continue after prev
+		b.WriteByte(rankDigits[p])
+		n = rankBase
+		for {
+			p = rankDigit(prev, pos, -1)
+			pos++
+			if p != rankBase-1 {
+				break
+			}
+			b.WriteByte(rankDigits[rankBase-1])
+		}
+	}
+	b.WriteByte(rankDigits[(p+n+1)/2])
+
+	return b.String(), nil
+}
+
+// RankAfter returns a rank sorting after prev. Unlike RankBetween(prev, ""),
+// the returned ranks only grow by one digit every few calls,
+// which suits appending tasks at the end of a list.
+func RankAfter(prev string) (string, error) {
+	if err := validateRank(prev); err != nil {
+		return "", err
+	}
+	if prev == "" {
+		return RankBetween("", "")
+	}
+
+	if d := rankDigit(prev, 0, -1); d < rankBase-1 {
+		return rankDigits[d+1 : d+2], nil
+	}
+	r, err := RankAfter(prev[1:])
+	if err != nil {
+		return "", err
+	}
+	return prev[:1] + r, nil
+}
+
+// EvenRanks returns n increasing ranks evenly spread over the rank space,
+// as short as possible
+func EvenRanks(n int) []string {
+	length, space := 1, rankBase
+	for space <= n {
+		length++
+		space *= rankBase
+	}
+
+	ranks := make([]string, n)
+	digits := make([]byte, length)
+	for i := 0; i < n; i++ {
+		v := (i + 1) * space / (n + 1)
+		for k := length - 1; k >= 0; k-- {
+			digits[k] = rankDigits[v%rankBase]
+			v /= rankBase
+		}
+		ranks[i] = strings.TrimRight(string(digits), rankDigits[:1])
+	}
+	return ranks
+}
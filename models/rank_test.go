@@ -0,0 +1,96 @@
+package models
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		prev, next string
+	}{
+		{"", ""},
+		{"", "1"},
+		{"", "01"},
+		{"", "001"},
+		{"i", ""},
+		{"z", ""},
+		{"zz", ""},
+		{"a", "b"},
+		{"a", "a1"},
+		{"a", "a01"},
+		{"az", "b"},
+		{"azz", "b"},
+		{"1", "2"},
+		{"h", "i"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prev+"-"+tt.next, func(t *testing.T) {
+			r, err := RankBetween(tt.prev, tt.next)
+			assert.NoError(t, err)
+			assert.NoError(t, validateRank(r))
+			assert.Less(t, tt.prev, r)
+			if tt.next != "" {
+				assert.Less(t, r, tt.next)
+			}
+		})
+	}
+
+	t.Run("Invalid ranks", func(t *testing.T) {
+		_, err := RankBetween("b", "a")
+		assert.Error(t, err)
+		_, err = RankBetween("a", "a")
+		assert.Error(t, err)
+		_, err = RankBetween("A", "")
+		assert.Error(t, err)
+		_, err = RankBetween("a0", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("Random inserts keep the order", func(t *testing.T) {
+		ranks := []string{}
+		for i := 0; i < 1000; i++ {
+			k := rand.Intn(len(ranks) + 1)
+			var prev, next string
+			if k > 0 {
+				prev = ranks[k-1]
+			}
+			if k < len(ranks) {
+				next = ranks[k]
+			}
+			r, err := RankBetween(prev, next)
+			assert.NoError(t, err)
+			ranks = append(ranks[:k], append([]string{r}, ranks[k:]...)...)
+		}
+		assert.True(t, sort.StringsAreSorted(ranks))
+	})
+}
+
+func TestRankAfter(t *testing.T) {
+	prev := ""
+	for i := 0; i < 100; i++ {
+		r, err := RankAfter(prev)
+		assert.NoError(t, err)
+		assert.NoError(t, validateRank(r))
+		assert.Less(t, prev, r)
+		prev = r
+	}
+	assert.LessOrEqual(t, len(prev), 10)
+}
+
+func TestEvenRanks(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 35, 36, 1000} {
+		ranks := EvenRanks(n)
+		assert.Len(t, ranks, n)
+		for k, r := range ranks {
+			assert.NoError(t, validateRank(r))
+			if k > 0 {
+				assert.Less(t, ranks[k-1], r)
+			}
+		}
+	}
+	assert.Equal(t, []string{"i"}, EvenRanks(1))
+}
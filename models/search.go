@@ -0,0 +1,15 @@
+package models
+
+import "todo-go/search"
+
+// SearchResult is a task matching a full-text query with its relevance
+type SearchResult struct {
+	Task  Task    `json:"task"`
+	Score float64 `json:"score"`
+}
+
+type SearchRepository interface {
+	// SearchTasks returns at most limit tasks, out of the trash,
+	// matching the query, the most relevant first
+	SearchTasks(q search.Query, limit int) []SearchResult
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+)
+
+// StatsFilter restricts the statistics to a range of days and optionally to a project
+type StatsFilter struct {
+	// First day of the range
+	From time.Time
+	// Day following the last day of the range
+	To        time.Time
+	ProjectId *uint64
+}
+
+// Days returns the days of the range, formatted as time.DateOnly
+func (f StatsFilter) Days() []string {
+	days := make([]string, 0)
+	for d := f.From; d.Before(f.To); d = d.AddDate(0, 0, 1) {
+		days = append(days, d.Format(time.DateOnly))
+	}
+	return days
+}
+
+// ThroughputDay is the number of tasks created and completed during a day
+type ThroughputDay struct {
+	Date      string `json:"date"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+}
+
+// BurndownDay is the number of tasks still open at the end of a day,
+// open tasks being neither done nor cancelled
+type BurndownDay struct {
+	Date string `json:"date"`
+	Open int    `json:"open"`
+}
+
+// Stats are the statistics of the tasks over a range of days.
+// Counts by status and priority are the current ones, the other
+// statistics are computed from the history of the tasks.
+type Stats struct {
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Total      int            `json:"total"`
+	ByStatus   map[Status]int `json:"by_status"`
+	ByPriority map[string]int `json:"by_priority"`
+
+	// Number of tasks completed during the range, and their average lead time
+	// (from their creation to DONE) and cycle time (from INPROGRESS to DONE).
+	// Averages are left out when no completed task has the needed history.
+	Completed             int      `json:"completed"`
+	AverageLeadTimeHours  *float64 `json:"average_lead_time_hours,omitempty"`
+	AverageCycleTimeHours *float64 `json:"average_cycle_time_hours,omitempty"`
+
+	Throughput []ThroughputDay `json:"throughput"`
+	Burndown   []BurndownDay   `json:"burndown"`
+}
+
+type StatsRepository interface {
+	GetStats(f StatsFilter) *Stats
+}
+
+// PriorityName returns the name of the priority, eg: "High"
+func PriorityName(p Priority) string {
+	if p < Lowest || p > Highest {
+		return ""
+	}
+	return priorityNames[p]
+}
+
+// IsOpenStatus returns whether a task having the status still has to be done
+func IsOpenStatus(s Status) bool {
+	return s != StatusDone && s != StatusCancelled
+}
+
+// StatusChange is a status a task moved to, and when
+type StatusChange struct {
+	Status Status
+	At     time.Time
+}
+
+// StatusTimeline returns the statuses the task went through, in order, from its history.
+// Tasks without recorded history are considered in their current status since their creation.
+func StatusTimeline(t Task, history []HistoryEntry) []StatusChange {
+	timeline := make([]StatusChange, 0)
+	for _, e := range history {
+		for _, c := range e.Changes {
+			if c.Field != "status" {
+				continue
+			}
+			s, _ := c.New.(Status)
+			if str, ok := c.New.(string); ok {
+				s = Status(str)
+			}
+			// Deletions clear the status, the task is still in its former one in the trash
+			if s != "" {
+				timeline = append(timeline, StatusChange{Status: s, At: e.Timestamp})
+			}
+		}
+	}
+
+	if len(timeline) == 0 {
+		timeline = append(timeline, StatusChange{Status: t.Status, At: t.CreatedAt})
+	}
+	return timeline
+}
+
+// Completion returns when the task was last completed and when the work on it started,
+// ie: its first move to INPROGRESS before that. ok is false if the task isn't done.
+// started is zero if the task never was in progress.
+func Completion(timeline []StatusChange) (started, completed time.Time, ok bool) {
+	if len(timeline) == 0 || timeline[len(timeline)-1].Status != StatusDone {
+		return started, completed, false
+	}
+
+	k := len(timeline) - 1
+	for k > 0 && timeline[k-1].Status == StatusDone {
+		k--
+	}
+	completed = timeline[k].At
+	for _, c := range timeline[:k] {
+		if c.Status == StatusInProgress {
+			started = c.At
+			break
+		}
+	}
+	return started, completed, true
+}
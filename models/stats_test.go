@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsFilterDays(t *testing.T) {
+	from := time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)
+	f := StatsFilter{From: from, To: from.AddDate(0, 0, 3)}
+	assert.Equal(t, []string{"2024-02-28", "2024-02-29", "2024-03-01"}, f.Days())
+}
+
+func TestStatusTimeline(t *testing.T) {
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	task := Task{Status: StatusDone, CreatedAt: created}
+
+	assert.Equal(t, []StatusChange{{Status: StatusDone, At: created}}, StatusTimeline(task, nil))
+
+	history := []HistoryEntry{
+		{Action: HistoryActionCreate, Timestamp: created, Changes: []FieldChange{{Field: "title", New: "task"}, {Field: "status", New: Status(StatusToDo)}}},
+		{Action: HistoryActionUpdate, Timestamp: created.Add(time.Hour), Changes: []FieldChange{{Field: "status", Old: StatusToDo, New: "INPROGRESS"}}},
+		{Action: HistoryActionDelete, Timestamp: created.Add(2 * time.Hour), Changes: []FieldChange{{Field: "status", Old: Status(StatusInProgress), New: Status("")}}},
+	}
+	assert.Equal(t, []StatusChange{
+		{Status: StatusToDo, At: created},
+		{Status: StatusInProgress, At: created.Add(time.Hour)},
+	}, StatusTimeline(task, history))
+}
+
+func TestCompletion(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2024, 1, 1, h, 0, 0, 0, time.UTC) }
+
+	_, _, ok := Completion([]StatusChange{{StatusToDo, at(0)}, {StatusInProgress, at(1)}})
+	assert.False(t, ok)
+
+	started, completed, ok := Completion([]StatusChange{{StatusToDo, at(0)}, {StatusDone, at(2)}})
+	assert.True(t, ok)
+	assert.True(t, started.IsZero())
+	assert.Equal(t, at(2), completed)
+
+	// Reopened tasks are completed the last time they're done, the work started the first time
+	started, completed, ok = Completion([]StatusChange{
+		{StatusToDo, at(0)}, {StatusInProgress, at(1)}, {StatusDone, at(2)},
+		{StatusInProgress, at(3)}, {StatusDone, at(4)}, {StatusDone, at(5)},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, at(1), started)
+	assert.Equal(t, at(4), completed)
+}
+
+func TestPriorityName(t *testing.T) {
+	assert.Equal(t, "Lowest", PriorityName(Lowest))
+	assert.Equal(t, "High", PriorityName(High))
+	assert.Equal(t, "", PriorityName(Priority(9)))
+}
@@ -1,6 +1,14 @@
 package models
 
-import "time"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTaskChanged is returned when updating a task which doesn't match
+// the condition of the update anymore, eg: its status changed meanwhile
+var ErrTaskChanged = errors.New("task changed")
 
 type Priority int
 type Status string
@@ -15,22 +23,81 @@ const (
 	StatusToDo       = "TODO"
 	StatusInProgress = "INPROGRESS"
 	StatusDone       = "DONE"
+	StatusBlocked    = "BLOCKED"
+	StatusCancelled  = "CANCELLED"
 )
 
 type Task struct {
-	Id        uint64    `json:"id"`
-	Title     string    `json:"title"`
-	Body      string    `json:"body"`
-	Priority  Priority  `json:"priority"`
-	Status    Status    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id        uint64     `json:"id"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Priority  Priority   `json:"priority"`
+	Status    Status     `json:"status"`
+	ProjectId *uint64    `json:"project_id,omitempty"`
+	Position  string     `json:"position"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	Attachments  []Attachment           `json:"attachments,omitempty"`
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	// Percentage of checked items of the checklist, computed from the checklist
+	ChecklistCompletion *int `json:"checklist_completion,omitempty"`
+
+	// Estimated time to complete the task, zero if unknown
+	EstimateMinutes int `json:"estimate_minutes,omitempty"`
+
+	// Sanitized HTML rendering of the markdown body, only filled on demand
+	BodyHTML string `json:"body_html,omitempty"`
 }
 
 type TaskRepository interface {
 	GetTaskByID(id uint64) (*Task, error)
 	GetAllTasks() []Task
-	CreateTask(t Task) (uint64, error)
-	UpdateTask(t Task) error
+	FindTasks(f TaskFilter) []Task
+	CreateTask(t Task, c TaskCondition) (uint64, error)
+	UpdateTask(t Task, c TaskCondition) error
 	DeleteTask(id uint64) error
+	GetDeletedTasks() []Task
+	RestoreTask(id uint64) error
+	PurgeTask(id uint64) (*Task, error)
+	PurgeDeletedTasks(before time.Time) ([]Task, error)
+	AddAttachment(taskId uint64, a Attachment) (uint64, error)
+	DeleteAttachment(taskId uint64, attachmentId uint64) error
+	MoveTask(id uint64, m TaskMove) (*Task, error)
+	RebalancePositions() error
+	AddChecklistItem(taskId uint64, i ChecklistItem) (uint64, error)
+	ToggleChecklistItem(taskId uint64, itemId uint64) error
+	MoveChecklistItem(taskId uint64, itemId uint64, index int) error
+	DeleteChecklistItem(taskId uint64, itemId uint64) error
+	// HealthCheck returns an error if the repository can't serve requests
+	HealthCheck(ctx context.Context) error
+}
+
+// ContextualTaskRepository is a TaskRepository whose calls can be bound to the
+// context of a request, eg: to trace them as part of the request
+type ContextualTaskRepository interface {
+	TaskRepository
+	WithContext(ctx context.Context) TaskRepository
+}
+
+// TaskCondition is checked against the stored tasks atomically with a write
+type TaskCondition struct {
+	// Status the task must still have, any status if empty. Ignored by creations.
+	Status Status
+	// Maximum number of tasks having the status the task is put in, zero for no limit.
+	// Only checked when the task enters the status.
+	WIPLimit int
+}
+
+// TaskMove describes where to move a task: right before or right after another task,
+// and optionally to another status
+type TaskMove struct {
+	Before *uint64 `json:"before,omitempty"`
+	After  *uint64 `json:"after,omitempty"`
+	Status Status  `json:"status,omitempty"`
+	// Checked against the stored tasks atomically with the move
+	Condition TaskCondition `json:"-"`
 }
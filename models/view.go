@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// View is a named filter a user saved to list tasks again.
+// Shared views are visible to every user but only their owner can change them.
+type View struct {
+	Id     uint64 `json:"id"`
+	Name   string `json:"name"`
+	Owner  string `json:"owner"`
+	Shared bool   `json:"shared"`
+	// Filter expression, see ParseTaskQuery
+	Filter string `json:"filter,omitempty"`
+	// Comma separated list of fields to sort on, see ParseSortKeys
+	Sort string `json:"sort,omitempty"`
+	// Field the tasks are grouped by, see GroupTasks
+	GroupBy   string    `json:"group_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ViewRepository interface {
+	// GetViews returns the views of the user and the views shared by other users
+	GetViews(user string) []View
+	GetViewByID(id uint64) (*View, error)
+	CreateView(v View) (uint64, error)
+	UpdateView(v View) error
+	DeleteView(id uint64) error
+}
+
+// VisibleTo returns whether the user can see the view
+func (v *View) VisibleTo(user string) bool {
+	return v.Shared || v.Owner == user
+}
+
+// TaskFilter returns the filter selecting the tasks of the view
+func (v *View) TaskFilter() (TaskFilter, error) {
+	var f TaskFilter
+	if strings.TrimSpace(v.Filter) != "" {
+		e, err := ParseTaskQuery(v.Filter)
+		if err != nil {
+			return f, fmt.Errorf("invalid filter: %w", err)
+		}
+		f.Query = e
+	}
+
+	keys, err := ParseSortKeys(v.Sort)
+	if err != nil {
+		return f, err
+	}
+	f.Sort = keys
+
+	return f, nil
+}
+
+// Validate checks the view has a name, a valid filter, sort and grouping
+func (v *View) Validate() error {
+	if strings.TrimSpace(v.Name) == "" {
+		return fmt.Errorf("view name must not be empty")
+	}
+	if _, err := v.TaskFilter(); err != nil {
+		return err
+	}
+	if v.GroupBy != "" && !groupableTaskFields[v.GroupBy] && (!strings.HasPrefix(v.GroupBy, CustomFieldPrefix) || v.GroupBy == CustomFieldPrefix) {
+		return fmt.Errorf("tasks can't be grouped by %s", v.GroupBy)
+	}
+	return nil
+}
+
+// Task fields tasks can be grouped by, besides custom fields
+var groupableTaskFields = map[string]bool{
+	"status":     true,
+	"priority":   true,
+	"project_id": true,
+}
+
+// TaskGroup holds the tasks having the same value for the field they are grouped by.
+// Key is empty for tasks without value.
+type TaskGroup struct {
+	Key   string `json:"key"`
+	Tasks []Task `json:"tasks"`
+}
+
+// GroupTasks groups the tasks by field, keeping their order.
+// Groups are in the order their first task comes in.
+func GroupTasks(tasks []Task, field string) []TaskGroup {
+	groups := make([]TaskGroup, 0)
+	index := make(map[string]int)
+	for _, t := range tasks {
+		var key string
+		switch field {
+		case "status":
+			key = string(t.Status)
+		case "priority":
+			key = strconv.Itoa(int(t.Priority))
+		case "project_id":
+			if t.ProjectId != nil {
+				key = strconv.FormatUint(*t.ProjectId, 10)
+			}
+		default:
+			key = FormatCustomValue(t.CustomFields[strings.TrimPrefix(field, CustomFieldPrefix)])
+		}
+
+		k, ok := index[key]
+		if !ok {
+			k = len(groups)
+			index[key] = k
+			groups = append(groups, TaskGroup{Key: key})
+		}
+		groups[k].Tasks = append(groups[k].Tasks, t)
+	}
+	return groups
+}
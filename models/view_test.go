@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewValidate(t *testing.T) {
+	valid := View{Name: "mine", Filter: "status:TODO priority>=High", Sort: "-priority,cf.points", GroupBy: "cf.team"}
+	assert.NoError(t, valid.Validate())
+
+	f, err := valid.TaskFilter()
+	assert.NoError(t, err)
+	assert.NotNil(t, f.Query)
+	assert.Equal(t, []SortKey{{Field: "priority", Desc: true}, {Field: "cf.points"}}, f.Sort)
+
+	for _, v := range []View{
+		{Name: " "},
+		{Name: "view", Filter: "status:"},
+		{Name: "view", Sort: "body"},
+		{Name: "view", GroupBy: "title"},
+		{Name: "view", GroupBy: "cf."},
+	} {
+		assert.Error(t, v.Validate(), v)
+	}
+}
+
+func TestViewVisibleTo(t *testing.T) {
+	v := View{Owner: "john"}
+	assert.True(t, v.VisibleTo("john"))
+	assert.False(t, v.VisibleTo("jane"))
+	v.Shared = true
+	assert.True(t, v.VisibleTo("jane"))
+}
+
+func TestGroupTasks(t *testing.T) {
+	project := uint64(3)
+	tasks := []Task{
+		{Id: 0, Status: StatusDone, Priority: High, ProjectId: &project, CustomFields: map[string]interface{}{"points": float64(3)}},
+		{Id: 1, Status: StatusToDo, Priority: Low},
+		{Id: 2, Status: StatusDone, Priority: High, CustomFields: map[string]interface{}{"points": float64(3)}},
+	}
+
+	groups := GroupTasks(tasks, "status")
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "DONE", groups[0].Key)
+	assert.Len(t, groups[0].Tasks, 2)
+	assert.Equal(t, "TODO", groups[1].Key)
+
+	groups = GroupTasks(tasks, "priority")
+	assert.Equal(t, "3", groups[0].Key)
+
+	groups = GroupTasks(tasks, "project_id")
+	assert.Equal(t, "3", groups[0].Key)
+	assert.Equal(t, "", groups[1].Key)
+	assert.Len(t, groups[1].Tasks, 2)
+
+	groups = GroupTasks(tasks, "cf.points")
+	assert.Equal(t, "3", groups[0].Key)
+	assert.Equal(t, "", groups[1].Key)
+
+	assert.Empty(t, GroupTasks(nil, "status"))
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultWorkflowStatuses is the list of statuses of the default workflow.
+	// The first status is the one given to newly created tasks.
+	DefaultWorkflowStatuses = "TODO,INPROGRESS,DONE,BLOCKED,CANCELLED"
+
+	// DefaultWorkflowTransitions is the list of allowed transitions of the default workflow
+	DefaultWorkflowTransitions = "TODO->INPROGRESS,TODO->BLOCKED,TODO->CANCELLED," +
+		"INPROGRESS->TODO,INPROGRESS->DONE,INPROGRESS->BLOCKED,INPROGRESS->CANCELLED," +
+		"BLOCKED->TODO,BLOCKED->INPROGRESS,BLOCKED->CANCELLED," +
+		"DONE->INPROGRESS"
+)
+
+// Workflow describes the statuses a task can have and
+// the transitions allowed between them
+type Workflow struct {
+	Initial     Status              `json:"initial"`
+	Statuses    []Status            `json:"statuses"`
+	Transitions map[Status][]Status `json:"transitions"`
+}
+
+// NewWorkflow returns a new Workflow. The first status of statuses is
+// the initial one. Every status used in transitions must be declared in statuses.
+func NewWorkflow(statuses []Status, transitions map[Status][]Status) (*Workflow, error) {
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("workflow must declare at least one status")
+	}
+
+	wf := &Workflow{
+		Initial:     statuses[0],
+		Statuses:    make([]Status, 0, len(statuses)),
+		Transitions: make(map[Status][]Status, len(statuses)),
+	}
+	for _, s := range statuses {
+		if s == "" {
+			return nil, fmt.Errorf("workflow status must not be empty")
+		}
+		if wf.IsValid(s) {
+			return nil, fmt.Errorf("workflow status %s is declared twice", s)
+		}
+		wf.Statuses = append(wf.Statuses, s)
+		wf.Transitions[s] = []Status{}
+	}
+
+	for from, tos := range transitions {
+		if !wf.IsValid(from) {
+			return nil, fmt.Errorf("workflow transition from unknown status %s", from)
+		}
+		for _, to := range tos {
+			if !wf.IsValid(to) {
+				return nil, fmt.Errorf("workflow transition from %s to unknown status %s", from, to)
+			}
+			if !wf.CanTransition(from, to) {
+				wf.Transitions[from] = append(wf.Transitions[from], to)
+			}
+		}
+	}
+
+	return wf, nil
+}
+
+// ParseWorkflow builds a Workflow from its textual representation,
+// as found in the configuration.
+// statuses is a comma separated list of statuses, eg: "TODO,INPROGRESS,DONE"
+// transitions is a comma separated list of transitions, eg: "TODO->INPROGRESS,INPROGRESS->DONE"
+func ParseWorkflow(statuses, transitions string) (*Workflow, error) {
+	var s []Status
+	for _, v := range strings.Split(statuses, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			s = append(s, Status(v))
+		}
+	}
+
+	t := make(map[Status][]Status)
+	for _, v := range strings.Split(transitions, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		parts := strings.Split(v, "->")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid workflow transition %q: must be of the form FROM->TO", v)
+		}
+		from := Status(strings.TrimSpace(parts[0]))
+		to := Status(strings.TrimSpace(parts[1]))
+		t[from] = append(t[from], to)
+	}
+
+	return NewWorkflow(s, t)
+}
+
+// DefaultWorkflow returns the default workflow:
+// TODO -> INPROGRESS -> DONE with optional BLOCKED and CANCELLED statuses
+func DefaultWorkflow() *Workflow {
+	wf, err := ParseWorkflow(DefaultWorkflowStatuses, DefaultWorkflowTransitions)
+	if err != nil {
+		panic(err)
+	}
+	return wf
+}
+
+// IsValid returns whether the given status is part of the workflow
+func (wf *Workflow) IsValid(s Status) bool {
+	_, ok := wf.Transitions[s]
+	return ok
+}
+
+// CanTransition returns whether a task can move from one status to another.
+// Staying in the same status is always allowed.
+func (wf *Workflow) CanTransition(from, to Status) bool {
+	if from == to {
+		return wf.IsValid(from)
+	}
+	for _, s := range wf.Transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkflow(t *testing.T) {
+	t.Run("Parse default workflow", func(t *testing.T) {
+		wf, err := ParseWorkflow(DefaultWorkflowStatuses, DefaultWorkflowTransitions)
+		assert.NoError(t, err)
+		assert.Equal(t, Status(StatusToDo), wf.Initial)
+		assert.Len(t, wf.Statuses, 5)
+	})
+
+	t.Run("Parse workflow with spaces", func(t *testing.T) {
+		wf, err := ParseWorkflow(" OPEN , CLOSED ", " OPEN -> CLOSED ")
+		assert.NoError(t, err)
+		assert.Equal(t, Status("OPEN"), wf.Initial)
+		assert.True(t, wf.CanTransition("OPEN", "CLOSED"))
+	})
+
+	t.Run("Parse workflow without status", func(t *testing.T) {
+		_, err := ParseWorkflow("", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("Parse workflow with duplicated status", func(t *testing.T) {
+		_, err := ParseWorkflow("TODO,TODO", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("Parse workflow with invalid transition", func(t *testing.T) {
+		_, err := ParseWorkflow("TODO,DONE", "TODO=>DONE")
+		assert.Error(t, err)
+	})
+
+	t.Run("Parse workflow with transition to unknown status", func(t *testing.T) {
+		_, err := ParseWorkflow("TODO,DONE", "TODO->INPROGRESS")
+		assert.Error(t, err)
+	})
+
+	t.Run("Parse workflow with transition from unknown status", func(t *testing.T) {
+		_, err := ParseWorkflow("TODO,DONE", "INPROGRESS->DONE")
+		assert.Error(t, err)
+	})
+}
+
+func TestWorkflowCanTransition(t *testing.T) {
+	wf := DefaultWorkflow()
+
+	tests := []struct {
+		from, to Status
+		allowed  bool
+	}{
+		{StatusToDo, StatusInProgress, true},
+		{StatusInProgress, StatusDone, true},
+		{StatusToDo, StatusToDo, true},
+		{StatusToDo, StatusDone, false},
+		{StatusCancelled, StatusToDo, false},
+		{StatusToDo, "UNKNOWN", false},
+		{"UNKNOWN", "UNKNOWN", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, wf.CanTransition(tt.from, tt.to))
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrTimerRunning is returned when starting a timer for a user already running one
+var ErrTimerRunning = errors.New("timer already running")
+
+// Worklog is a period of time a user spent on a task.
+// Worklogs of running timers have no end yet.
+type Worklog struct {
+	Id        uint64     `json:"id"`
+	TaskId    uint64     `json:"task_id"`
+	User      string     `json:"user"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Minutes   int        `json:"minutes"`
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Running returns whether the worklog is a running timer
+func (wl *Worklog) Running() bool {
+	return wl.EndedAt == nil
+}
+
+// WorklogFilter selects the worklogs started in [From, To).
+// Zero times and an empty user don't filter.
+type WorklogFilter struct {
+	From time.Time
+	To   time.Time
+	User string
+}
+
+type WorklogRepository interface {
+	GetTaskWorklogs(taskId uint64) []Worklog
+	FindWorklogs(f WorklogFilter) []Worklog
+	GetWorklogByID(id uint64) (*Worklog, error)
+	GetRunningWorklog(user string) (*Worklog, error)
+	CreateWorklog(wl Worklog) (uint64, error)
+	UpdateWorklog(wl Worklog) error
+	DeleteWorklog(id uint64) error
+}
+
+type TimeReportGroup string
+
+const (
+	TimeReportByTask    TimeReportGroup = "task"
+	TimeReportByProject TimeReportGroup = "project"
+	TimeReportByUser    TimeReportGroup = "user"
+)
+
+// TimeReportRow is the time logged on a task, a project or by a user.
+// The estimate is the sum of the estimates of the tasks time was logged on,
+// it is left out of rows grouped by user.
+type TimeReportRow struct {
+	TaskId          *uint64 `json:"task_id,omitempty"`
+	ProjectId       *uint64 `json:"project_id,omitempty"`
+	User            string  `json:"user,omitempty"`
+	LoggedMinutes   int     `json:"logged_minutes"`
+	EstimateMinutes int     `json:"estimate_minutes,omitempty"`
+}
+
+// TimeReport aggregates the time logged over a date range
+type TimeReport struct {
+	GroupBy         TimeReportGroup `json:"group_by"`
+	Rows            []TimeReportRow `json:"rows"`
+	LoggedMinutes   int             `json:"logged_minutes"`
+	EstimateMinutes int             `json:"estimate_minutes"`
+}
+
+// NewTimeReport aggregates the minutes of the worklogs by task, project or user.
+// tasks are used to find the estimate and the project of the tasks of the worklogs.
+// Running timers are left out, their time is only known once they are stopped.
+func NewTimeReport(groupBy TimeReportGroup, tasks []Task, worklogs []Worklog) (*TimeReport, error) {
+	switch groupBy {
+	case TimeReportByTask, TimeReportByProject, TimeReportByUser:
+	default:
+		return nil, fmt.Errorf("invalid group %s: must be one of task, project, user", groupBy)
+	}
+
+	byId := make(map[uint64]*Task, len(tasks))
+	for k := range tasks {
+		byId[tasks[k].Id] = &tasks[k]
+	}
+
+	r := &TimeReport{GroupBy: groupBy, Rows: make([]TimeReportRow, 0)}
+	rows := make(map[string]int)
+	estimated := make(map[uint64]bool)
+	for _, wl := range worklogs {
+		if wl.Running() {
+			continue
+		}
+		t := byId[wl.TaskId]
+
+		var key string
+		var row TimeReportRow
+		switch groupBy {
+		case TimeReportByTask:
+			key = fmt.Sprint(wl.TaskId)
+			id := wl.TaskId
+			row.TaskId = &id
+		case TimeReportByProject:
+			if t != nil && t.ProjectId != nil {
+				key = fmt.Sprint(*t.ProjectId)
+				id := *t.ProjectId
+				row.ProjectId = &id
+			}
+		case TimeReportByUser:
+			key = wl.User
+			row.User = wl.User
+		}
+
+		k, ok := rows[key]
+		if !ok {
+			k = len(r.Rows)
+			rows[key] = k
+			r.Rows = append(r.Rows, row)
+		}
+		r.Rows[k].LoggedMinutes += wl.Minutes
+		r.LoggedMinutes += wl.Minutes
+
+		// Count the estimate of each task once
+		if t != nil && !estimated[t.Id] {
+			estimated[t.Id] = true
+			r.EstimateMinutes += t.EstimateMinutes
+			if groupBy != TimeReportByUser {
+				r.Rows[k].EstimateMinutes += t.EstimateMinutes
+			}
+		}
+	}
+
+	sort.SliceStable(r.Rows, func(i, j int) bool {
+		a, b := r.Rows[i], r.Rows[j]
+		switch groupBy {
+		case TimeReportByTask:
+			return *a.TaskId < *b.TaskId
+		case TimeReportByProject:
+			// Tasks without project come last
+			if a.ProjectId == nil || b.ProjectId == nil {
+				return b.ProjectId == nil && a.ProjectId != nil
+			}
+			return *a.ProjectId < *b.ProjectId
+		default:
+			return a.User < b.User
+		}
+	})
+
+	return r, nil
+}
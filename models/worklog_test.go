@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTimeReport(t *testing.T) {
+	project := uint64(7)
+	tasks := []Task{
+		{Id: 0, ProjectId: &project, EstimateMinutes: 60},
+		{Id: 1, ProjectId: &project, EstimateMinutes: 30},
+		{Id: 2},
+	}
+	end := time.Now()
+	worklogs := []Worklog{
+		{TaskId: 0, User: "john", Minutes: 45, EndedAt: &end},
+		{TaskId: 0, User: "jane", Minutes: 30, EndedAt: &end},
+		{TaskId: 1, User: "john", Minutes: 10, EndedAt: &end},
+		{TaskId: 2, User: "jane", Minutes: 5, EndedAt: &end},
+		// Running timers are not reported
+		{TaskId: 2, User: "john"},
+	}
+
+	t.Run("Report by task", func(t *testing.T) {
+		r, err := NewTimeReport(TimeReportByTask, tasks, worklogs)
+		assert.NoError(t, err)
+		assert.Len(t, r.Rows, 3)
+		assert.Equal(t, uint64(0), *r.Rows[0].TaskId)
+		assert.Equal(t, 75, r.Rows[0].LoggedMinutes)
+		assert.Equal(t, 60, r.Rows[0].EstimateMinutes)
+		assert.Equal(t, 90, r.LoggedMinutes)
+		assert.Equal(t, 90, r.EstimateMinutes)
+	})
+
+	t.Run("Report by project", func(t *testing.T) {
+		r, err := NewTimeReport(TimeReportByProject, tasks, worklogs)
+		assert.NoError(t, err)
+		assert.Len(t, r.Rows, 2)
+		assert.Equal(t, project, *r.Rows[0].ProjectId)
+		assert.Equal(t, 85, r.Rows[0].LoggedMinutes)
+		assert.Equal(t, 90, r.Rows[0].EstimateMinutes)
+		assert.Nil(t, r.Rows[1].ProjectId)
+		assert.Equal(t, 5, r.Rows[1].LoggedMinutes)
+	})
+
+	t.Run("Report by user", func(t *testing.T) {
+		r, err := NewTimeReport(TimeReportByUser, tasks, worklogs)
+		assert.NoError(t, err)
+		assert.Len(t, r.Rows, 2)
+		assert.Equal(t, "jane", r.Rows[0].User)
+		assert.Equal(t, 35, r.Rows[0].LoggedMinutes)
+		assert.Equal(t, 0, r.Rows[0].EstimateMinutes)
+		assert.Equal(t, "john", r.Rows[1].User)
+		assert.Equal(t, 55, r.Rows[1].LoggedMinutes)
+		assert.Equal(t, 90, r.EstimateMinutes)
+	})
+
+	t.Run("Report by unknown group", func(t *testing.T) {
+		_, err := NewTimeReport("status", tasks, worklogs)
+		assert.Error(t, err)
+	})
+}
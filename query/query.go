@@ -0,0 +1,296 @@
+// Package query parses filter expressions such as
+// `status:TODO priority>=High (created_at<2026-11-01 OR NOT project_id:none)`.
+//
+// Expressions are made of comparisons FIELD OP VALUE, bare words and quoted
+// strings combined with AND, OR, NOT and parentheses. Terms next to each
+// other are implicitly joined with AND, which binds tighter than OR.
+// Operators are ":", "=", "!=", "<", "<=", ">" and ">=". Values with spaces
+// or operators are quoted with double quotes.
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is a comparison operator
+type Op string
+
+const (
+	OpMatch        Op = ":"
+	OpEqual        Op = "="
+	OpNotEqual     Op = "!="
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+)
+
+// Expr is a node of the syntax tree of an expression
+type Expr interface {
+	String() string
+}
+
+// And matches when both its operands match
+type And struct {
+	Left, Right Expr
+}
+
+// Or matches when any of its operands matches
+type Or struct {
+	Left, Right Expr
+}
+
+// Not matches when its operand doesn't match
+type Not struct {
+	Expr Expr
+}
+
+// Comparison compares a field to a value. Bare words and quoted strings
+// are comparisons without field and with the OpMatch operator.
+type Comparison struct {
+	Field string
+	Op    Op
+	Value string
+	// Pos is the position of the comparison in the expression, starting at 1
+	Pos int
+	// Resolved is the value converted to the type of the field, set by the users
+	// of the syntax tree once they have checked the comparison
+	Resolved interface{}
+}
+
+func (e *And) String() string { return "(" + e.Left.String() + " AND " + e.Right.String() + ")" }
+func (e *Or) String() string  { return "(" + e.Left.String() + " OR " + e.Right.String() + ")" }
+func (e *Not) String() string { return "NOT " + e.Expr.String() }
+func (e *Comparison) String() string {
+	return e.Field + string(e.Op) + fmt.Sprintf("%q", e.Value)
+}
+
+// Walk calls fn on every comparison of the expression, in order,
+// until fn returns an error
+func Walk(e Expr, fn func(c *Comparison) error) error {
+	switch e := e.(type) {
+	case *And:
+		if err := Walk(e.Left, fn); err != nil {
+			return err
+		}
+		return Walk(e.Right, fn)
+	case *Or:
+		if err := Walk(e.Left, fn); err != nil {
+			return err
+		}
+		return Walk(e.Right, fn)
+	case *Not:
+		return Walk(e.Expr, fn)
+	case *Comparison:
+		return fn(e)
+	}
+	return nil
+}
+
+// Error is an error in an expression, at a given position
+type Error struct {
+	// Pos is the position of the error in the expression, starting at 1
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return fmt.Sprintf("%q", t.value)
+	}
+	return t.value
+}
+
+// Characters ending words
+const special = " \t\r\n()\":=!<>"
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case strings.IndexByte(" \t\r\n", c) >= 0:
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i + 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i + 1})
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return nil, &Error{Pos: i + 1, Msg: "missing closing quote"}
+			}
+			tokens = append(tokens, token{tokenString, s[i+1 : i+1+end], i + 1})
+			i += end + 2
+		case strings.IndexByte(":=!<>", c) >= 0:
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' && c != ':' && c != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &Error{Pos: i + 1, Msg: "unknown operator !, did you mean != ?"}
+			}
+			tokens = append(tokens, token{tokenOp, op, i + 1})
+			i += len(op)
+		default:
+			end := strings.IndexAny(s[i:], special)
+			if end < 0 {
+				end = len(s) - i
+			}
+			tokens = append(tokens, token{tokenWord, s[i : i+end], i + 1})
+			i += end
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(s) + 1}), nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+// Parse parses an expression into its syntax tree
+func Parse(s string) (Expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 1, Msg: "filter is empty"}
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return e, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) pop() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenWord && t.value == keyword
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.pop()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if p.isKeyword("AND") {
+			p.pop()
+		} else if t := p.peek(); t.kind == tokenEOF || t.kind == tokenRParen || p.isKeyword("OR") {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.isKeyword("NOT") {
+		p.pop()
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.pop()
+	switch t.kind {
+	case tokenLParen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, &Error{Pos: t.pos, Msg: "missing closing parenthesis"}
+		}
+		p.pop()
+		return e, nil
+	case tokenString:
+		return &Comparison{Op: OpMatch, Value: t.value, Pos: t.pos}, nil
+	case tokenWord:
+		if t.value == "AND" || t.value == "OR" {
+			return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a term before %s", t.value)}
+		}
+		if p.peek().kind != tokenOp {
+			return &Comparison{Op: OpMatch, Value: t.value, Pos: t.pos}, nil
+		}
+		op := p.pop()
+		v := p.pop()
+		if v.kind != tokenWord && v.kind != tokenString {
+			return nil, &Error{Pos: v.pos, Msg: fmt.Sprintf("expected a value after %s%s, got %s", t.value, op.value, v)}
+		}
+		return &Comparison{Field: t.value, Op: Op(op.value), Value: v.value, Pos: t.pos}, nil
+	case tokenOp:
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a field before %s", t.value)}
+	}
+	return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a term, got %s", t)}
+}
@@ -0,0 +1,67 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		tree string
+	}{
+		{"Comparison", `status:TODO`, `status:"TODO"`},
+		{"Operators", `a=1 b!=2 c<3 d<=4 e>5 f>=6`, `(((((a="1" AND b!="2") AND c<"3") AND d<="4") AND e>"5") AND f>="6")`},
+		{"Bare words and strings", `login "two words"`, `(:"login" AND :"two words")`},
+		{"Quoted values", `title:"a (b) c"`, `title:"a (b) c"`},
+		{"AND binds tighter than OR", `a:1 OR b:2 c:3`, `(a:"1" OR (b:"2" AND c:"3"))`},
+		{"Explicit AND", `a:1 AND b:2`, `(a:"1" AND b:"2")`},
+		{"Parentheses", `a:1 (b:2 OR NOT c:3)`, `(a:"1" AND (b:"2" OR NOT c:"3"))`},
+		{"Dates", `created_at<2026-11-01`, `created_at<"2026-11-01"`},
+		{"Package example", `status:TODO priority>=High (created_at<2026-11-01 OR NOT project_id:none)`, `((status:"TODO" AND priority>="High") AND (created_at<"2026-11-01" OR NOT project_id:"none"))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.tree, e.String())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{``, "position 1: filter is empty"},
+		{`status:`, "position 8: expected a value after status:, got end of filter"},
+		{`status:"TODO`, `position 8: missing closing quote`},
+		{`(a:1 OR b:2`, "position 1: missing closing parenthesis"},
+		{`a:1)`, "position 4: unexpected )"},
+		{`OR a:1`, "position 1: expected a term before OR"},
+		{`a:1 OR`, "position 7: expected a term, got end of filter"},
+		{`:1`, "position 1: expected a field before :"},
+		{`a!1`, "position 2: unknown operator !, did you mean != ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	e, _ := Parse(`a:1 (b:2 OR NOT c:3)`)
+	var fields []string
+	Walk(e, func(c *Comparison) error {
+		fields = append(fields, c.Field)
+		return nil
+	})
+	assert.Equal(t, []string{"a", "b", "c"}, fields)
+}
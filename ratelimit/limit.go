@@ -0,0 +1,124 @@
+// Package ratelimit limits the rate of the requests of each client with token buckets
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket: it holds up to Burst tokens, refilled at Rate tokens per second.
+// Each request takes a token, requests are denied while the bucket is empty.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the state of a bucket after a request took a token from it, or failed to
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Time until a token is available, zero if the request is allowed
+	RetryAfter time.Duration
+	// Time until the bucket is full again
+	Reset time.Duration
+}
+
+// Store holds the buckets of the clients
+type Store interface {
+	// Take takes a token from the bucket key, refilled according to l
+	Take(ctx context.Context, key string, l Limit, now time.Time) (Result, error)
+}
+
+// take takes a token from a bucket holding tokens at last, refilled according to l.
+// It returns the result and the tokens left in the bucket at now.
+func take(tokens float64, last time.Time, l Limit, now time.Time) (Result, float64) {
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(l.Burst), tokens+elapsed*l.Rate)
+	}
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return newResult(allowed, tokens, l), tokens
+}
+
+// newResult returns the result of a request, given the tokens left in the bucket after it
+func newResult(allowed bool, tokens float64, l Limit) Result {
+	r := Result{
+		Allowed:   allowed,
+		Remaining: int(tokens),
+		Reset:     seconds((float64(l.Burst) - tokens) / l.Rate),
+	}
+	if !allowed {
+		r.RetryAfter = seconds((1 - tokens) / l.Rate)
+	}
+	return r
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+var limitUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// ParseLimit parses a limit of the form N/unit, eg: "10/m" for 10 requests per minute
+// with bursts of 10 requests. unit is one of s, m, h.
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid limit %q: must be of the form N/unit, eg: 10/m", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: the number of requests must be positive", s)
+	}
+	unit, ok := limitUnits[strings.TrimSpace(parts[1])]
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q: the unit must be one of s, m, h", s)
+	}
+
+	return Limit{Rate: float64(n) / unit.Seconds(), Burst: n}, nil
+}
+
+// ParseRouteLimits parses a comma separated list of limits by route, eg:
+// "POST /task=10/m,GET /search=5/s". Routes are a method and a path template.
+// A limit of "none" lifts the default limit of the route.
+func ParseRouteLimits(s string) (map[string]*Limit, error) {
+	limits := make(map[string]*Limit)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		k := strings.LastIndex(v, "=")
+		if k == -1 {
+			return nil, fmt.Errorf("invalid route limit %q: must be of the form METHOD /path=N/unit", v)
+		}
+		route := strings.Join(strings.Fields(v[:k]), " ")
+		if len(strings.Fields(route)) != 2 {
+			return nil, fmt.Errorf("invalid route limit %q: the route must be a method and a path", v)
+		}
+		if _, ok := limits[route]; ok {
+			return nil, fmt.Errorf("route %s is limited twice", route)
+		}
+
+		if strings.TrimSpace(v[k+1:]) == "none" {
+			limits[route] = nil
+			continue
+		}
+		l, err := ParseLimit(v[k+1:])
+		if err != nil {
+			return nil, err
+		}
+		limits[route] = &l
+	}
+	return limits, nil
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("10/m")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, l.Burst)
+	assert.InDelta(t, 10.0/60, l.Rate, 1e-9)
+
+	l, err = ParseLimit(" 5 / s ")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 5, Burst: 5}, l)
+
+	for _, v := range []string{"", "10", "10/d", "0/s", "-1/s", "a/s", "1/s/s"} {
+		_, err := ParseLimit(v)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	limits, err := ParseRouteLimits("POST /task=10/m, GET  /task/{id:[0-9]+}=5/s,GET /healthz=none,")
+	assert.NoError(t, err)
+	assert.Len(t, limits, 3)
+	assert.Equal(t, &Limit{Rate: 5, Burst: 5}, limits["GET /task/{id:[0-9]+}"])
+	assert.Equal(t, 10, limits["POST /task"].Burst)
+	l, ok := limits["GET /healthz"]
+	assert.True(t, ok)
+	assert.Nil(t, l)
+
+	for _, v := range []string{"POST /task", "/task=10/m", "POST /task=10/m,POST /task=5/m", "POST /task=fast"} {
+		_, err := ParseRouteLimits(v)
+		assert.Error(t, err, v)
+	}
+}
+
+// testStore checks the behavior of a token bucket store
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := Limit{Rate: 1, Burst: 3}
+
+	t.Run("Take tokens of a burst", func(t *testing.T) {
+		for i := 2; i >= 0; i-- {
+			r, err := s.Take(ctx, "client", l, now)
+			assert.NoError(t, err)
+			assert.True(t, r.Allowed)
+			assert.Equal(t, i, r.Remaining)
+			assert.Equal(t, time.Duration(3-i)*time.Second, r.Reset)
+		}
+
+		r, err := s.Take(ctx, "client", l, now)
+		assert.NoError(t, err)
+		assert.False(t, r.Allowed)
+		assert.Equal(t, 0, r.Remaining)
+		assert.Equal(t, time.Second, r.RetryAfter)
+	})
+
+	t.Run("Buckets are per key", func(t *testing.T) {
+		r, err := s.Take(ctx, "other", l, now)
+		assert.NoError(t, err)
+		assert.True(t, r.Allowed)
+	})
+
+	t.Run("Refill the bucket", func(t *testing.T) {
+		r, _ := s.Take(ctx, "client", l, now.Add(500*time.Millisecond))
+		assert.False(t, r.Allowed)
+		assert.Equal(t, 500*time.Millisecond, r.RetryAfter)
+
+		r, _ = s.Take(ctx, "client", l, now.Add(1500*time.Millisecond))
+		assert.True(t, r.Allowed)
+		assert.Equal(t, 0, r.Remaining)
+
+		// The bucket doesn't hold more than the burst
+		r, _ = s.Take(ctx, "client", l, now.Add(time.Hour))
+		assert.True(t, r.Allowed)
+		assert.Equal(t, 2, r.Remaining)
+	})
+}
@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := Limit{Rate: 1, Burst: 10}
+
+	s.Take(context.Background(), "idle", l, now)
+	s.Take(context.Background(), "busy", Limit{Rate: 0.001, Burst: 10}, now)
+	assert.Len(t, s.buckets, 2)
+
+	// The idle bucket is full again after 1s, the busy one after 1000s
+	s.Take(context.Background(), "new", l, now.Add(2*sweepInterval))
+	assert.Len(t, s.buckets, 2)
+	assert.NotContains(t, s.buckets, "idle")
+}
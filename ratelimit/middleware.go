@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Limiter limits the rate of the requests of each client, route by route
+type Limiter struct {
+	store        Store
+	defaultLimit *Limit
+	routeLimits  map[string]*Limit
+	apiKeyHeader string
+	apiKeys      map[string]bool
+	trustProxy   bool
+	now          func() time.Time
+}
+
+// Option configures a Limiter
+type Option func(*Limiter)
+
+// WithDefaultLimit sets the limit of the routes without their own limit.
+// They aren't limited if none is given.
+func WithDefaultLimit(l *Limit) Option {
+	return func(lim *Limiter) {
+		lim.defaultLimit = l
+	}
+}
+
+// WithRouteLimits sets the limits of routes, see ParseRouteLimits.
+// A nil limit lifts the default limit of the route.
+func WithRouteLimits(limits map[string]*Limit) Option {
+	return func(lim *Limiter) {
+		lim.routeLimits = limits
+	}
+}
+
+// WithAPIKeyHeader identifies the clients by the API key they send in the header,
+// provided it is one of the keys given to WithAPIKeys. The clients sending no key
+// or an unknown one are identified by their IP address.
+func WithAPIKeyHeader(header string) Option {
+	return func(lim *Limiter) {
+		lim.apiKeyHeader = header
+	}
+}
+
+// WithAPIKeys sets the API keys identifying clients, see WithAPIKeyHeader.
+// Empty keys are ignored.
+func WithAPIKeys(keys []string) Option {
+	return func(lim *Limiter) {
+		for _, k := range keys {
+			if k = strings.TrimSpace(k); k != "" {
+				lim.apiKeys[hashKey(k)] = true
+			}
+		}
+	}
+}
+
+// WithTrustedProxy identifies the clients by the IP address given by the proxy
+// in front of the service in the X-Forwarded-For header
+func WithTrustedProxy(trust bool) Option {
+	return func(lim *Limiter) {
+		lim.trustProxy = trust
+	}
+}
+
+// NewLimiter returns a limiter keeping the buckets of the clients in store
+func NewLimiter(store Store, opts ...Option) *Limiter {
+	lim := &Limiter{
+		store:       store,
+		routeLimits: make(map[string]*Limit),
+		apiKeys:     make(map[string]bool),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(lim)
+	}
+	return lim
+}
+
+// limit returns the limit of the route, nil if it isn't limited
+func (lim *Limiter) limit(route string) *Limit {
+	if l, ok := lim.routeLimits[route]; ok {
+		return l
+	}
+	return lim.defaultLimit
+}
+
+// hashKey returns the hash of an API key, so that keys aren't kept in clear
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// client identifies the client of the request by its API key or its IP address.
+// Unknown API keys are ignored: otherwise clients could get a fresh bucket with
+// every request by sending random keys.
+func (lim *Limiter) client(r *http.Request) string {
+	if lim.apiKeyHeader != "" {
+		if key := r.Header.Get(lim.apiKeyHeader); key != "" {
+			if h := hashKey(key); lim.apiKeys[h] {
+				return "key:" + h
+			}
+		}
+	}
+
+	if lim.trustProxy {
+		// The closest address is the one added by the proxy, the others can be forged
+		if v := r.Header.Get("X-Forwarded-For"); v != "" {
+			addrs := strings.Split(v, ",")
+			return "ip:" + strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ceilSeconds returns the duration in whole seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// Middleware limits the requests to the routes of a mux.Router, see mux.Router.Use.
+// Requests over the limit are answered 429 with a Retry-After header. Limited routes
+// report the state of the bucket in RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset.
+// Requests are let through if the store fails, rather than failing the service with it.
+func (lim *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.URL.Path
+		if cr := mux.CurrentRoute(r); cr != nil {
+			if tpl, err := cr.GetPathTemplate(); err == nil {
+				route = tpl
+			}
+		}
+		route = r.Method + " " + route
+
+		l := lim.limit(route)
+		if l == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res, err := lim.store.Take(r.Context(), route+"|"+lim.client(r), *l, lim.now())
+		if err != nil {
+			log.Printf("failed to rate limit %s: %s", route, err.Error())
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(l.Burst))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		w.Header().Set("RateLimit-Reset", ceilSeconds(res.Reset))
+		if !res.Allowed {
+			w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("rate limit exceeded"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, l Limit, now time.Time) (Result, error) {
+	return Result{}, fmt.Errorf("connection refused")
+}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newRouter := func(store Store, opts ...Option) *mux.Router {
+		lim := NewLimiter(store, opts...)
+		lim.now = func() time.Time { return now }
+
+		r := mux.NewRouter()
+		r.Use(lim.Middleware)
+		ok := func(w http.ResponseWriter, r *http.Request) {}
+		r.HandleFunc("/task", ok).Methods("POST")
+		r.HandleFunc("/task/{id:[0-9]+}", ok).Methods("GET")
+		r.HandleFunc("/healthz", ok).Methods("GET")
+		return r
+	}
+	do := func(r *mux.Router, method, path, remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = remoteAddr
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		return res
+	}
+
+	defaultLimit := Limit{Rate: 1, Burst: 5}
+	r := newRouter(NewMemoryStore(),
+		WithDefaultLimit(&defaultLimit),
+		WithRouteLimits(map[string]*Limit{"POST /task": {Rate: 1.0 / 60, Burst: 2}, "GET /healthz": nil}),
+		WithAPIKeyHeader("X-API-Key"),
+		WithAPIKeys([]string{"secret", " "}),
+	)
+
+	t.Run("Limit a route", func(t *testing.T) {
+		res := do(r, "POST", "/task", "10.0.0.1:1234", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "2", res.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", res.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", res.Header().Get("RateLimit-Reset"))
+
+		res = do(r, "POST", "/task", "10.0.0.1:5678", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		res = do(r, "POST", "/task", "10.0.0.1:1234", nil)
+		assert.Equal(t, http.StatusTooManyRequests, res.Code)
+		assert.Equal(t, "60", res.Header().Get("Retry-After"))
+		assert.Equal(t, "0", res.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "rate limit exceeded", res.Body.String())
+	})
+
+	t.Run("Limit clients separately", func(t *testing.T) {
+		res := do(r, "POST", "/task", "10.0.0.2:1234", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		res = do(r, "POST", "/task", "10.0.0.1:1234", http.Header{"X-Api-Key": {"secret"}})
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("Ignore unknown API keys", func(t *testing.T) {
+		res := do(r, "POST", "/task", "10.0.0.3:1234", http.Header{"X-Api-Key": {"random-1"}})
+		assert.Equal(t, http.StatusOK, res.Code)
+		res = do(r, "POST", "/task", "10.0.0.3:1234", http.Header{"X-Api-Key": {"random-2"}})
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		// Rotating keys doesn't escape the limit of the IP address
+		res = do(r, "POST", "/task", "10.0.0.3:1234", http.Header{"X-Api-Key": {"random-3"}})
+		assert.Equal(t, http.StatusTooManyRequests, res.Code)
+		res = do(r, "POST", "/task", "10.0.0.3:1234", nil)
+		assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	})
+
+	t.Run("Limit routes separately", func(t *testing.T) {
+		res := do(r, "GET", "/task/1", "10.0.0.1:1234", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "5", res.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "4", res.Header().Get("RateLimit-Remaining"))
+
+		// Path variables share the bucket of the route
+		res = do(r, "GET", "/task/2", "10.0.0.1:1234", nil)
+		assert.Equal(t, "3", res.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("Lift the default limit of a route", func(t *testing.T) {
+		res := do(r, "GET", "/healthz", "10.0.0.1:1234", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, res.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("Trust the proxy", func(t *testing.T) {
+		r := newRouter(NewMemoryStore(), WithDefaultLimit(&Limit{Rate: 1, Burst: 1}), WithTrustedProxy(true))
+		forwarded := func(ip string) http.Header { return http.Header{"X-Forwarded-For": {"1.2.3.4, " + ip}} }
+
+		assert.Equal(t, http.StatusOK, do(r, "POST", "/task", "10.0.0.1:1234", forwarded("192.168.0.1")).Code)
+		assert.Equal(t, http.StatusOK, do(r, "POST", "/task", "10.0.0.1:1234", forwarded("192.168.0.2")).Code)
+		assert.Equal(t, http.StatusTooManyRequests, do(r, "POST", "/task", "10.0.0.1:1234", forwarded("192.168.0.1")).Code)
+	})
+
+	t.Run("Let requests through when the store fails", func(t *testing.T) {
+		r := newRouter(failingStore{}, WithDefaultLimit(&defaultLimit))
+		res := do(r, "POST", "/task", "10.0.0.1:1234", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, res.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("No limit by default", func(t *testing.T) {
+		r := newRouter(failingStore{})
+		assert.Equal(t, http.StatusOK, do(r, "POST", "/task", "10.0.0.1:1234", nil).Code)
+	})
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript takes a token from the bucket stored in the hash KEYS[1] atomically.
+// ARGV holds the rate in tokens per second, the burst and the current time in milliseconds.
+// It returns whether the request is allowed and the tokens left, as a string to keep the fraction.
+// The bucket expires once it is full again.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(state[1]) or burst
+local last = tonumber(state[2]) or now
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) / 1000 * rate)
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "last", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore holds the buckets in Redis so that they are shared by the instances of the service
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore returns a store keeping the buckets in client, under keys starting with prefix
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, l Limit, now time.Time) (Result, error) {
+	args := []interface{}{strconv.FormatFloat(l.Rate, 'f', -1, 64), l.Burst, now.UnixMilli()}
+	v, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, args...).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take a token from redis: %w", err)
+	}
+	if len(v) != 2 {
+		return Result{}, fmt.Errorf("unexpected reply from redis: %v", v)
+	}
+
+	allowed, _ := v[0].(int64)
+	tokens, err := strconv.ParseFloat(fmt.Sprint(v[1]), 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected reply from redis: %v", v)
+	}
+	return newResult(allowed == 1, tokens, l), nil
+}
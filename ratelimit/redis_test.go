@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	testStore(t, NewRedisStore(client, "test:"))
+
+	t.Run("Buckets expire once full", func(t *testing.T) {
+		assert.True(t, mr.Exists("test:client"))
+		mr.FastForward(3 * time.Second)
+		assert.False(t, mr.Exists("test:client"))
+	})
+
+	t.Run("Fail when redis is down", func(t *testing.T) {
+		mr.Close()
+		_, err := NewRedisStore(client, "test:").Take(context.Background(), "client", Limit{Rate: 1, Burst: 1}, time.Now())
+		assert.Error(t, err)
+	})
+}
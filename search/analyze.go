@@ -0,0 +1,23 @@
+// Package search implements the full-text search of tasks:
+// text analysis, query parsing and an inverted index ranking documents with BM25.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/kljensen/snowball/english"
+)
+
+// Tokenize splits the text into lowercase words.
+// Words are sequences of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Stem returns the stem of a lowercase word, eg: "running" -> "run"
+func Stem(word string) string {
+	return english.Stem(word, true)
+}
@@ -0,0 +1,217 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// Weight of the words of titles over the words of bodies
+	titleBoost = 2.0
+
+	// BM25 parameters: term frequency saturation and length normalization
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Hit is a document matching a query with its relevance
+type Hit struct {
+	Id    uint64
+	Score float64
+}
+
+type document struct {
+	// Number of words of the title and the body
+	length int
+	// Positions of the stems, the title comes first
+	positions map[string][]int
+	// Positions below titleLength are in the title
+	titleLength int
+	// Distinct words, to maintain the vocabulary
+	words []string
+}
+
+// Index is an inverted index of documents made of a title and a body.
+// It is updated incrementally as documents are added and removed.
+// It isn't safe for concurrent use.
+type Index struct {
+	docs     map[uint64]*document
+	postings map[string]map[uint64]bool
+	// Number of documents containing each word, used to expand prefixes
+	words       map[string]int
+	totalLength int
+}
+
+// NewIndex returns a new empty Index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[uint64]*document),
+		postings: make(map[string]map[uint64]bool),
+		words:    make(map[string]int),
+	}
+}
+
+// Add indexes a document, replacing the document with the same id if any
+func (idx *Index) Add(id uint64, title, body string) {
+	idx.Remove(id)
+
+	titleWords, bodyWords := Tokenize(title), Tokenize(body)
+	d := &document{
+		length:      len(titleWords) + len(bodyWords),
+		positions:   make(map[string][]int),
+		titleLength: len(titleWords),
+	}
+
+	seen := make(map[string]bool)
+	add := func(words []string, offset int) {
+		for k, w := range words {
+			s := Stem(w)
+			d.positions[s] = append(d.positions[s], offset+k)
+			if !seen[w] {
+				seen[w] = true
+				d.words = append(d.words, w)
+			}
+		}
+	}
+	add(titleWords, 0)
+	// Leave a gap so phrases don't span the title and the body
+	add(bodyWords, len(titleWords)+1)
+
+	for s := range d.positions {
+		if idx.postings[s] == nil {
+			idx.postings[s] = make(map[uint64]bool)
+		}
+		idx.postings[s][id] = true
+	}
+	for _, w := range d.words {
+		idx.words[w]++
+	}
+	idx.docs[id] = d
+	idx.totalLength += d.length
+}
+
+// Remove removes a document from the index
+func (idx *Index) Remove(id uint64) {
+	d, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for s := range d.positions {
+		delete(idx.postings[s], id)
+		if len(idx.postings[s]) == 0 {
+			delete(idx.postings, s)
+		}
+	}
+	for _, w := range d.words {
+		if idx.words[w]--; idx.words[w] == 0 {
+			delete(idx.words, w)
+		}
+	}
+	delete(idx.docs, id)
+	idx.totalLength -= d.length
+}
+
+// Search returns the documents matching all the terms of the query,
+// the most relevant first. Relevance is computed with BM25.
+func (idx *Index) Search(q Query) []Hit {
+	if len(idx.docs) == 0 {
+		return []Hit{}
+	}
+
+	scores := make(map[uint64]float64)
+	for k, t := range q.Terms {
+		occurrences := idx.match(t)
+
+		// Keep the documents matching all the terms so far
+		if k > 0 {
+			for id := range scores {
+				if _, ok := occurrences[id]; !ok {
+					delete(scores, id)
+				}
+			}
+		}
+
+		n, total := float64(len(occurrences)), float64(len(idx.docs))
+		idf := math.Log(1 + (total-n+0.5)/(n+0.5))
+		avgLength := float64(idx.totalLength) / total
+		for id, tf := range occurrences {
+			if _, ok := scores[id]; k > 0 && !ok {
+				continue
+			}
+			length := float64(idx.docs[id].length)
+			scores[id] += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*length/avgLength))
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, Hit{Id: id, Score: s})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Id < hits[j].Id
+	})
+	return hits
+}
+
+// match returns the weighted number of occurrences of the term by document
+func (idx *Index) match(t Term) map[uint64]float64 {
+	occurrences := make(map[uint64]float64)
+
+	if t.Prefix {
+		stems := make(map[string]bool)
+		for w := range idx.words {
+			if strings.HasPrefix(w, t.Tokens[0]) {
+				stems[Stem(w)] = true
+			}
+		}
+		for s := range stems {
+			for id := range idx.postings[s] {
+				d := idx.docs[id]
+				for _, p := range d.positions[s] {
+					occurrences[id] += d.weight(p)
+				}
+			}
+		}
+		return occurrences
+	}
+
+	for id := range idx.postings[t.Tokens[0]] {
+		d := idx.docs[id]
+		for _, p := range d.positions[t.Tokens[0]] {
+			if d.phraseAt(t.Tokens, p) {
+				occurrences[id] += d.weight(p)
+			}
+		}
+	}
+	return occurrences
+}
+
+// weight returns the weight of the word at position p
+func (d *document) weight(p int) float64 {
+	if p < d.titleLength {
+		return titleBoost
+	}
+	return 1
+}
+
+// phraseAt returns whether the stems follow each other from position p
+func (d *document) phraseAt(stems []string, p int) bool {
+	for k, s := range stems[1:] {
+		found := false
+		for _, v := range d.positions[s] {
+			if v == p+k+1 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ids(hits []Hit) []uint64 {
+	ids := make([]uint64, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, h.Id)
+	}
+	return ids
+}
+
+func search(t *testing.T, idx *Index, q string) []uint64 {
+	query, err := ParseQuery(q)
+	assert.NoError(t, err)
+	return ids(idx.Search(query))
+}
+
+func TestIndex(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(0, "Login page", "The login page crashes when running on mobile")
+	idx.Add(1, "Integration tests", "Run the integration tests against the staging login")
+	idx.Add(2, "Write release notes", "Mention the new page about logins")
+
+	t.Run("Search words", func(t *testing.T) {
+		// Titles rank above bodies
+		hits := search(t, idx, "login")
+		assert.Len(t, hits, 3)
+		assert.Equal(t, uint64(0), hits[0])
+		// Stemming
+		assert.ElementsMatch(t, []uint64{0, 1}, search(t, idx, "runs"))
+		// All words must match
+		assert.Equal(t, []uint64{0, 2}, search(t, idx, "login page"))
+		assert.Empty(t, search(t, idx, "login unknown"))
+	})
+
+	t.Run("Search phrases", func(t *testing.T) {
+		assert.Equal(t, []uint64{0}, search(t, idx, `"login page"`))
+		assert.Equal(t, []uint64{1}, search(t, idx, `"staging login"`))
+		// Phrases don't span the title and the body
+		assert.Empty(t, search(t, idx, `"tests run"`))
+	})
+
+	t.Run("Search prefixes", func(t *testing.T) {
+		assert.Equal(t, []uint64{1}, search(t, idx, "integ*"))
+		assert.Equal(t, []uint64{2}, search(t, idx, "rel*"))
+		assert.ElementsMatch(t, []uint64{0, 1, 2}, search(t, idx, "log*"))
+	})
+
+	t.Run("Update documents", func(t *testing.T) {
+		idx.Add(2, "Write release notes", "Nothing else")
+		assert.Equal(t, []uint64{0, 1}, search(t, idx, "login"))
+
+		idx.Remove(1)
+		assert.Equal(t, []uint64{0}, search(t, idx, "login"))
+		assert.Empty(t, search(t, idx, "integ*"))
+		idx.Remove(1)
+	})
+
+	t.Run("Search empty index", func(t *testing.T) {
+		assert.Empty(t, search(t, NewIndex(), "login"))
+	})
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Term is a part of a query documents must match. It is either
+// a word, a phrase of consecutive words or the prefix of a word.
+type Term struct {
+	// Stems of the words of the term, or the lowercase prefix of prefix terms
+	Tokens []string
+	Prefix bool
+}
+
+// Query is a full-text query. Documents must match all its terms.
+type Query struct {
+	Terms []Term
+}
+
+// ParseQuery parses a full-text query made of space separated terms:
+//   - word: documents containing the word or a word with the same stem
+//   - "some words": documents containing the words next to each other
+//   - pref*: documents containing a word starting with pref
+func ParseQuery(q string) (Query, error) {
+	var query Query
+
+	for q = strings.TrimSpace(q); q != ""; q = strings.TrimSpace(q) {
+		if q[0] == '"' {
+			end := strings.IndexByte(q[1:], '"')
+			if end < 0 {
+				return query, fmt.Errorf("unterminated phrase %s", q)
+			}
+			if t := phraseTerm(q[1 : end+1]); len(t.Tokens) > 0 {
+				query.Terms = append(query.Terms, t)
+			}
+			q = q[end+2:]
+			continue
+		}
+
+		word := q
+		if end := strings.IndexAny(q, " \t\n\""); end >= 0 {
+			word = q[:end]
+		}
+		q = q[len(word):]
+
+		if prefix := strings.TrimSuffix(word, "*"); prefix != word {
+			tokens := Tokenize(prefix)
+			if len(tokens) == 0 {
+				continue
+			}
+			// The prefix applies to the last word, the others are plain words
+			for _, t := range tokens[:len(tokens)-1] {
+				query.Terms = append(query.Terms, Term{Tokens: []string{Stem(t)}})
+			}
+			query.Terms = append(query.Terms, Term{Tokens: tokens[len(tokens)-1:], Prefix: true})
+			continue
+		}
+
+		// Words joined by punctuation, like "e-mail", are searched as a phrase
+		if t := phraseTerm(word); len(t.Tokens) > 0 {
+			query.Terms = append(query.Terms, t)
+		}
+	}
+
+	if len(query.Terms) == 0 {
+		return query, fmt.Errorf("query must contain at least one word")
+	}
+	return query, nil
+}
+
+func phraseTerm(text string) Term {
+	var t Term
+	for _, w := range Tokenize(text) {
+		t.Tokens = append(t.Tokens, Stem(w))
+	}
+	return t
+}
@@ -0,0 +1,32 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"fix", "the", "e", "mail", "löschen", "v2"}, Tokenize("Fix the e-mail: Löschen (v2)!"))
+	assert.Empty(t, Tokenize(" -- "))
+}
+
+func TestParseQuery(t *testing.T) {
+	t.Run("Parse queries", func(t *testing.T) {
+		q, err := ParseQuery(`Running "login pages" integr* e-mail`)
+		assert.NoError(t, err)
+		assert.Equal(t, []Term{
+			{Tokens: []string{"run"}},
+			{Tokens: []string{"login", "page"}},
+			{Tokens: []string{"integr"}, Prefix: true},
+			{Tokens: []string{"e", "mail"}},
+		}, q.Terms)
+	})
+
+	t.Run("Parse invalid queries", func(t *testing.T) {
+		for _, s := range []string{"", "  ", `"unterminated`, "* ..."} {
+			_, err := ParseQuery(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package tracing
+
+import (
+	"context"
+	"time"
+	"todo-go/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TaskRepository wraps a models.TaskRepository to trace its calls.
+// The spans are children of the span of the context the repository
+// is bound to with WithContext, or start new traces otherwise.
+type TaskRepository struct {
+	repo   models.TaskRepository
+	tracer trace.Tracer
+	ctx    context.Context
+}
+
+// NewTaskRepository wraps repo and traces its calls with the tracers of tp
+func NewTaskRepository(repo models.TaskRepository, tp trace.TracerProvider) *TaskRepository {
+	return &TaskRepository{
+		repo:   repo,
+		tracer: tp.Tracer(instrumentationName),
+		ctx:    context.Background(),
+	}
+}
+
+// WithContext returns a copy of the repository whose spans are children of the span of ctx
+func (r *TaskRepository) WithContext(ctx context.Context) models.TaskRepository {
+	c := *r
+	c.ctx = ctx
+	return &c
+}
+
+// start starts the span of an operation
+func (r *TaskRepository) start(ctx context.Context, op string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := r.tracer.Start(ctx, "TaskRepository."+op, trace.WithAttributes(attrs...))
+	return span
+}
+
+// end ends the span of an operation, recording its error if any
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func taskID(id uint64) attribute.KeyValue {
+	return attribute.Int64("task.id", int64(id))
+}
+
+func (r *TaskRepository) GetTaskByID(id uint64) (*models.Task, error) {
+	span := r.start(r.ctx, "GetTaskByID", taskID(id))
+	v, err := r.repo.GetTaskByID(id)
+	end(span, err)
+	return v, err
+}
+
+func (r *TaskRepository) GetAllTasks() []models.Task {
+	span := r.start(r.ctx, "GetAllTasks")
+	v := r.repo.GetAllTasks()
+	end(span, nil)
+	return v
+}
+
+func (r *TaskRepository) FindTasks(f models.TaskFilter) []models.Task {
+	span := r.start(r.ctx, "FindTasks")
+	v := r.repo.FindTasks(f)
+	end(span, nil)
+	return v
+}
+
+func (r *TaskRepository) CreateTask(t models.Task, c models.TaskCondition) (uint64, error) {
+	span := r.start(r.ctx, "CreateTask")
+	v, err := r.repo.CreateTask(t, c)
+	end(span, err)
+	return v, err
+}
+
+func (r *TaskRepository) UpdateTask(t models.Task, c models.TaskCondition) error {
+	span := r.start(r.ctx, "UpdateTask", taskID(t.Id))
+	err := r.repo.UpdateTask(t, c)
+	end(span, err)
+	return err
+}
+
+func (r *TaskRepository) DeleteTask(id uint64) error {
+	span := r.start(r.ctx, "DeleteTask", taskID(id))
+	err := r.repo.DeleteTask(id)
+	end(span, err)
+	return err
+}
+
+func (r *TaskRepository) GetDeletedTasks() []models.Task {
+	span := r.start(r.ctx, "GetDeletedTasks")
+	v := r.repo.GetDeletedTasks()
+	end(span, nil)
+	return v
+}
+
+func (r *TaskRepository) RestoreTask(id uint64) error {
+	span := r.start(r.ctx, "RestoreTask", taskID(id))
+	err := r.repo.RestoreTask(id)
+	end(span, err)
+	return err
+}
+
+func (r *TaskRepository) PurgeTask(id uint64) (*models.Task, error) {
+	span := r.start(r.ctx, "PurgeTask", taskID(id))
+	v, err := r.repo.PurgeTask(id)
+	end(span, err)
+	return v, err
+}
+
+func (r *TaskRepository) PurgeDeletedTasks(before time.Time) ([]models.Task, error) {
+	span := r.start(r.ctx, "PurgeDeletedTasks")
+	v, err := r.repo.PurgeDeletedTasks(before)
+	end(span, err)
+	return v, err
+}
+
+func (r *TaskRepository) AddAttachment(taskId uint64, a models.Attachment) (uint64, error) {
+	span := r.start(r.ctx, "AddAttachment", taskID(taskId))
+	v, err := r.repo.AddAttachment(taskId, a)
+	end(span, err)
+	return v, err
+}
+
+func (r *TaskRepository) DeleteAttachment(taskId uint64, attachmentId uint64) error {
+	span := r.start(r.ctx, "DeleteAttachment", taskID(taskId))
+	err := r.repo.DeleteAttachment(taskId, attachmentId)
+	end(span, err)
+	return err
+}
+
+func (r *TaskRepository) MoveTask(id uint64, m models.TaskMove) (*models.Task, error) {
+	span := r.start(r.ctx, "MoveTask", taskID(id))
+	v, err := r.repo.MoveTask(id, m)
+	end(span, err)
+	return v, err
+}
+
+func (r *TaskRepository) RebalancePositions() error {
+	span := r.start(r.ctx, "RebalancePositions")
+	err := r.repo.RebalancePositions()
+	end(span, err)
+	return err
+}
+
+func (r *TaskRepository) AddChecklistItem(taskId uint64, i models.ChecklistItem) (uint64, error) {
+	span := r.start(r.ctx, "AddChecklistItem", taskID(taskId))
+	v, err := r.repo.AddChecklistItem(taskId, i)
+	end(span, err)
+	return v, err
+}
+
+func (r *TaskRepository) ToggleChecklistItem(taskId uint64, itemId uint64) error {
+	span := r.start(r.ctx, "ToggleChecklistItem", taskID(taskId))
+	err := r.repo.ToggleChecklistItem(taskId, itemId)
+	end(span, err)
+	return err
+}
+
+func (r *TaskRepository) MoveChecklistItem(taskId uint64, itemId uint64, index int) error {
+	span := r.start(r.ctx, "MoveChecklistItem", taskID(taskId))
+	err := r.repo.MoveChecklistItem(taskId, itemId, index)
+	end(span, err)
+	return err
+}
+
+func (r *TaskRepository) DeleteChecklistItem(taskId uint64, itemId uint64) error {
+	span := r.start(r.ctx, "DeleteChecklistItem", taskID(taskId))
+	err := r.repo.DeleteChecklistItem(taskId, itemId)
+	end(span, err)
+	return err
+}
+
+// HealthCheck traces the check as part of ctx rather than the bound context
+func (r *TaskRepository) HealthCheck(ctx context.Context) error {
+	span := r.start(ctx, "HealthCheck")
+	err := r.repo.HealthCheck(ctx)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,85 @@
+// Package tracing traces the requests and the repository calls with OpenTelemetry
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Spans are dropped
+	ExporterNone = "none"
+	// Spans are sent to an OpenTelemetry collector with OTLP over HTTP
+	ExporterOTLP = "otlp"
+
+	// Name of the tracer of the spans created by this package
+	instrumentationName = "todo-go/tracing"
+)
+
+// Options configures the export of the spans
+type Options struct {
+	ServiceName string
+	Exporter    string
+	// host:port of the OTLP collector
+	Endpoint string
+	// Sends the spans over HTTP instead of HTTPS
+	Insecure bool
+	// Fraction of the traces started by the service that are sampled, from 0 to 1.
+	// Traces propagated by the caller follow its sampling decision.
+	SampleRatio float64
+}
+
+// NewTracerProvider returns a tracer provider exporting the spans as configured by opts.
+// It must be shut down to flush the spans not exported yet.
+func NewTracerProvider(ctx context.Context, opts Options) (*sdktrace.TracerProvider, error) {
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", opts.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	}
+
+	switch opts.Exporter {
+	case ExporterNone:
+	case ExporterOTLP:
+		clientOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("invalid exporter %s: must be one of none, otlp", opts.Exporter)
+	}
+
+	return sdktrace.NewTracerProvider(tpOpts...), nil
+}
+
+// Propagator propagates the trace context in the W3C traceparent and tracestate headers
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// Middleware starts a span for each request served by the routes of a mux.Router,
+// see mux.Router.Use. The span continues the trace propagated by the caller and is
+// named after the method and the path template of the route, ie: after the handler.
+func Middleware(service string, tp trace.TracerProvider) mux.MiddlewareFunc {
+	return otelmux.Middleware(service,
+		otelmux.WithTracerProvider(tp),
+		otelmux.WithPropagators(Propagator()),
+		otelmux.WithSpanNameFormatter(func(route string, r *http.Request) string {
+			return r.Method + " " + route
+		}),
+	)
+}
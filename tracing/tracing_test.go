@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-go/controllers"
+	"todo-go/databases"
+	"todo-go/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	for _, exporter := range []string{ExporterNone, ExporterOTLP} {
+		tp, err := NewTracerProvider(context.Background(), Options{ServiceName: "test", Exporter: exporter, Endpoint: "localhost:4318", SampleRatio: 1})
+		assert.NoError(t, err, exporter)
+		assert.NoError(t, tp.Shutdown(context.Background()))
+	}
+
+	_, err := NewTracerProvider(context.Background(), Options{Exporter: "jaeger"})
+	assert.EqualError(t, err, "invalid exporter jaeger: must be one of none, otlp")
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	db := databases.NewInMemoryDatabase()
+	db.CreateTask(models.Task{Title: "traced"}, models.TaskCondition{})
+	h := controllers.NewBaseHandler(NewTaskRepository(db, tp))
+
+	r := mux.NewRouter()
+	r.Use(Middleware("test", tp))
+	r.HandleFunc("/task/{id:[0-9]+}", h.GetTaskByID).Methods("GET")
+
+	get := func(path string) {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("Trace a request", func(t *testing.T) {
+		exporter.Reset()
+		get("/task/0")
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		repo, handler := spans[0], spans[1]
+		assert.Equal(t, "TaskRepository.GetTaskByID", repo.Name)
+		assert.Equal(t, "GET /task/{id:[0-9]+}", handler.Name)
+
+		// The request continues the trace of the caller and the repository call is part of it
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", handler.SpanContext.TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", handler.Parent.SpanID().String())
+		assert.Equal(t, handler.SpanContext.SpanID(), repo.Parent.SpanID())
+		assert.Equal(t, handler.SpanContext.TraceID(), repo.SpanContext.TraceID())
+	})
+
+	t.Run("Trace a failed repository call", func(t *testing.T) {
+		exporter.Reset()
+		get("/task/99")
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, "Error", spans[0].Status.Code.String())
+		assert.Len(t, spans[0].Events, 1)
+	})
+
+	t.Run("Trace calls outside of a request", func(t *testing.T) {
+		exporter.Reset()
+		NewTaskRepository(db, tp).GetAllTasks()
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.False(t, spans[0].Parent.IsValid())
+	})
+}